@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/virel-project/virel-blockchain/v3/config"
+	"github.com/virel-project/virel-gui/v2/lang"
+)
+
+var (
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrTooManyDecimals  = fmt.Errorf("amount has more than %d decimals", config.ATOMIC)
+	ErrAmountOutOfRange = errors.New("amount out of range")
+)
+
+// parseAtomic converts a decimal string like "12.5" to atomic units, without
+// any rounding. Amounts with more than config.ATOMIC decimals are rejected.
+func parseAtomic(s string) (uint64, error) {
+	intPart, frac, _ := strings.Cut(s, ".")
+	if intPart == "" && frac == "" {
+		return 0, ErrInvalidAmount
+	}
+	if !isDigits(intPart) || !isDigits(frac) {
+		return 0, ErrInvalidAmount
+	}
+	if len(frac) > int(config.ATOMIC) {
+		return 0, ErrTooManyDecimals
+	}
+
+	digits := intPart + frac + strings.Repeat("0", int(config.ATOMIC)-len(frac))
+	n, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, ErrAmountOutOfRange
+		}
+		return 0, ErrInvalidAmount
+	}
+	return n, nil
+}
+
+// formatAtomic converts atomic units to a decimal string with config.ATOMIC
+// decimals
+func formatAtomic(n uint64) string {
+	s := fmt.Sprintf("%0*d", int(config.ATOMIC)+1, n)
+	return s[:len(s)-int(config.ATOMIC)] + "." + s[len(s)-int(config.ATOMIC):]
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAmount parses an amount typed with the number format of the language
+// and returns it in atomic units
+func parseAmount(s string) (uint64, error) {
+	dec, err := T.ParseDecimal(s)
+	if errors.Is(err, lang.ErrAmbiguousNumber) {
+		return 0, err
+	} else if err != nil {
+		return 0, ErrInvalidAmount
+	}
+	return parseAtomic(dec)
+}
+
+// amountError returns the translated message for an error of parseAmount
+func amountError(err error) error {
+	if errors.Is(err, ErrTooManyDecimals) {
+		return fmt.Errorf(T.ErrTooManyDecimals, config.ATOMIC)
+	}
+	if errors.Is(err, lang.ErrAmbiguousNumber) {
+		return fmt.Errorf(T.ErrAmbiguousAmount, T.FormatDecimal("1.5"), T.FormatDecimal("1500"))
+	}
+	return errors.New(T.InvalidAmount)
+}
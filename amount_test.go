@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/virel-project/virel-blockchain/v3/config"
+)
+
+func TestParseAtomic(t *testing.T) {
+	atomic := int(config.ATOMIC)
+	maxUint := strconv.FormatUint(math.MaxUint64, 10)
+	maxAmount := maxUint[:len(maxUint)-atomic] + "." + maxUint[len(maxUint)-atomic:]
+	// one atomic unit more than math.MaxUint64, which ends in 5
+	overflow := maxAmount[:len(maxAmount)-1] + "6"
+
+	tests := []struct {
+		input string
+		want  uint64
+		err   error
+	}{
+		{"", 0, ErrInvalidAmount},
+		{".", 0, ErrInvalidAmount},
+		{"5.", 5 * config.COIN, nil},
+		{".5", config.COIN / 2, nil},
+		{"0", 0, nil},
+		{"1", config.COIN, nil},
+		{"12.5", 12*config.COIN + config.COIN/2, nil},
+		{"0." + strings.Repeat("0", atomic-1) + "1", 1, nil},
+		{"1." + strings.Repeat("9", atomic), 2*config.COIN - 1, nil},
+		{"0." + strings.Repeat("0", atomic) + "1", 0, ErrTooManyDecimals},
+		{"1." + strings.Repeat("0", atomic+1), 0, ErrTooManyDecimals},
+		{"007.5", 7*config.COIN + config.COIN/2, nil},
+		{strings.Repeat("0", 30) + "1", config.COIN, nil},
+		{maxAmount, math.MaxUint64, nil},
+		{overflow, 0, ErrAmountOutOfRange},
+		{"1" + maxUint, 0, ErrAmountOutOfRange},
+		{"-1", 0, ErrInvalidAmount},
+		{"+1", 0, ErrInvalidAmount},
+		{"1.-5", 0, ErrInvalidAmount},
+		{"1.2.3", 0, ErrInvalidAmount},
+		{" 1", 0, ErrInvalidAmount},
+		{"1e9", 0, ErrInvalidAmount},
+		{"1,5", 0, ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAtomic(tt.input)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("parseAtomic(%q) = %d, %v, want %d, %v", tt.input, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestFormatAtomic(t *testing.T) {
+	zeros := strings.Repeat("0", int(config.ATOMIC))
+	tests := []struct {
+		input uint64
+		want  string
+	}{
+		{0, "0." + zeros},
+		{1, "0." + zeros[1:] + "1"},
+		{config.COIN, "1." + zeros},
+		{12*config.COIN + config.COIN/2, "12.5" + zeros[1:]},
+	}
+	for _, tt := range tests {
+		if got := formatAtomic(tt.input); got != tt.want {
+			t.Errorf("formatAtomic(%d) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	for _, n := range []uint64{0, 1, config.COIN - 1, config.COIN, 123456789012345, math.MaxUint64 - 1, math.MaxUint64} {
+		got, err := parseAtomic(formatAtomic(n))
+		if err != nil || got != n {
+			t.Errorf("parseAtomic(formatAtomic(%d)) = %d, %v", n, got, err)
+		}
+	}
+}
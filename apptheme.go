@@ -0,0 +1,64 @@
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+const (
+	themeSystem = ""
+	themeLight  = "light"
+	themeDark   = "dark"
+)
+
+// variantTheme is the default theme forced to the light or dark variant
+type variantTheme struct {
+	fyne.Theme
+	variant fyne.ThemeVariant
+}
+
+func (t *variantTheme) Color(n fyne.ThemeColorName, _ fyne.ThemeVariant) color.Color {
+	return t.Theme.Color(n, t.variant)
+}
+
+// applyTheme sets the theme of the app, themeSystem follows the system
+func applyTheme(name string) {
+	switch name {
+	case themeLight:
+		a.Settings().SetTheme(&variantTheme{theme.DefaultTheme(), theme.VariantLight})
+	case themeDark:
+		a.Settings().SetTheme(&variantTheme{theme.DefaultTheme(), theme.VariantDark})
+	default:
+		a.Settings().SetTheme(theme.DefaultTheme())
+	}
+}
+
+// themeSelect lets the user choose the theme, which is saved in the settings
+func themeSelect(current string) *widget.Select {
+	names := map[string]string{
+		T.ThemeSystem: themeSystem,
+		T.ThemeLight:  themeLight,
+		T.ThemeDark:   themeDark,
+	}
+	sel := widget.NewSelect([]string{T.ThemeSystem, T.ThemeLight, T.ThemeDark}, nil)
+	for k, v := range names {
+		if v == current {
+			sel.SetSelected(k)
+		}
+	}
+	sel.OnChanged = func(s string) {
+		name := names[s]
+		applyTheme(name)
+		err := save.UpdateSettings(func(s *save.Settings) {
+			s.Theme = name
+		})
+		if err != nil {
+			ErrorDialog(w, err)
+		}
+	}
+	return sel
+}
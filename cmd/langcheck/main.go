@@ -0,0 +1,65 @@
+// Command langcheck reports the missing and unused keys of the translation
+// files, the strings whose format verbs don't match English, and the messages
+// whose placeholders don't match English.
+//
+// Run it from the repository root:
+//
+//	go run ./cmd/langcheck
+//
+// It exits with status 1 if a file can't be parsed, has unused keys,
+// mismatched verbs or mismatched placeholders. With -strict, missing keys are errors too.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/virel-project/virel-gui/v2/lang"
+)
+
+var dir = flag.String("dir", "lang/translations", "directory of the translation files")
+var strict = flag.Bool("strict", false, "treat missing keys as errors")
+
+func main() {
+	flag.Parse()
+
+	reports, err := lang.Check(os.DirFS(*dir))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "langcheck:", err)
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, r := range reports {
+		if r.HasErrors() || (*strict && len(r.Missing) > 0) {
+			failed = true
+		}
+
+		if r.ParseErr != nil {
+			fmt.Printf("%s: %v\n", r.File, r.ParseErr)
+			continue
+		}
+		if len(r.Missing) == 0 && !r.HasErrors() {
+			fmt.Printf("%s: ok\n", r.File)
+			continue
+		}
+		fmt.Printf("%s:\n", r.File)
+		printKeys("missing", r.Missing)
+		printKeys("unused", r.Unused)
+		printKeys("format verbs differ from English", r.BadVerbs)
+		printKeys("placeholders differ from English", r.BadMessages)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printKeys(title string, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	fmt.Printf("  %s (%d): %s\n", title, len(keys), strings.Join(keys, ", "))
+}
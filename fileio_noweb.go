@@ -0,0 +1,58 @@
+//go:build !js
+// +build !js
+
+package main
+
+import (
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+)
+
+// exportFile asks the user where to save data
+func exportFile(filename string, data []byte) {
+	d := dialog.NewFileSave(func(uc fyne.URIWriteCloser, err error) {
+		if err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		if uc == nil {
+			return
+		}
+		defer uc.Close()
+
+		_, err = uc.Write(data)
+		if err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		InfoDialog(w, T.ExportWallet, T.WalletExported)
+	}, w)
+	d.SetFileName(filename)
+	d.Show()
+}
+
+// importFile asks the user for a wallet file and calls onLoad with its content
+func importFile(onLoad func(filename string, data []byte)) {
+	d := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+		if err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		if rc == nil {
+			return
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		onLoad(rc.URI().Name(), data)
+	}, w)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{".keys"}))
+	d.Show()
+}
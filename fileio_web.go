@@ -0,0 +1,69 @@
+//go:build js
+// +build js
+
+package main
+
+import (
+	"syscall/js"
+
+	"fyne.io/fyne/v2"
+)
+
+// exportFile makes the browser download data
+func exportFile(filename string, data []byte) {
+	arr := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(arr, data)
+
+	blob := js.Global().Get("Blob").New([]any{arr}, map[string]any{
+		"type": "application/octet-stream",
+	})
+	url := js.Global().Get("URL").Call("createObjectURL", blob)
+	defer js.Global().Get("URL").Call("revokeObjectURL", url)
+
+	document := js.Global().Get("document")
+	link := document.Call("createElement", "a")
+	link.Set("href", url)
+	link.Set("download", filename)
+	document.Get("body").Call("appendChild", link)
+	link.Call("click")
+	link.Call("remove")
+}
+
+// importFile opens the browser's file picker and calls onLoad with the content
+// of the chosen file
+func importFile(onLoad func(filename string, data []byte)) {
+	document := js.Global().Get("document")
+	input := document.Call("createElement", "input")
+	input.Set("type", "file")
+	input.Set("accept", ".keys")
+
+	var onChange, onRead js.Func
+	onChange = js.FuncOf(func(this js.Value, args []js.Value) any {
+		onChange.Release()
+
+		files := input.Get("files")
+		if files.Length() == 0 {
+			return nil
+		}
+		file := files.Index(0)
+		filename := file.Get("name").String()
+
+		onRead = js.FuncOf(func(this js.Value, args []js.Value) any {
+			onRead.Release()
+
+			arr := js.Global().Get("Uint8Array").New(args[0])
+			data := make([]byte, arr.Get("length").Int())
+			js.CopyBytesToGo(data, arr)
+
+			// JS callbacks must not block
+			go fyne.Do(func() {
+				onLoad(filename, data)
+			})
+			return nil
+		})
+		file.Call("arrayBuffer").Call("then", onRead)
+		return nil
+	})
+	input.Call("addEventListener", "change", onChange)
+	input.Call("click")
+}
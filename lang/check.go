@@ -0,0 +1,148 @@
+package lang
+
+import (
+	"io/fs"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Report lists the problems found in a translation file
+type Report struct {
+	File string
+	// ParseErr is set when the file is not valid TOML
+	ParseErr error
+	// Missing are the keys of Translation that the file does not define. They
+	// fall back to English.
+	Missing []string
+	// Unused are the keys of the file that are not in Translation
+	Unused []string
+	// BadVerbs are the keys whose format verbs don't match English in count
+	// and order
+	BadVerbs []string
+	// BadMessages are the Message keys that are invalid, or whose placeholders
+	// don't match English
+	BadMessages []string
+}
+
+// HasErrors reports whether the file has problems other than missing keys
+func (r *Report) HasErrors() bool {
+	return r.ParseErr != nil || len(r.Unused) > 0 || len(r.BadVerbs) > 0 || len(r.BadMessages) > 0
+}
+
+var verbRegex = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]*)?[a-zA-Z%]`)
+
+// formatVerbs returns the fmt verbs of s, in order, ignoring "%%"
+func formatVerbs(s string) []string {
+	verbs := []string{}
+	for _, v := range verbRegex.FindAllString(s, -1) {
+		if v != "%%" {
+			verbs = append(verbs, v)
+		}
+	}
+	return verbs
+}
+
+// translationKeys returns the keys of Translation, in declaration order, and
+// whether they are a Message
+func translationKeys() ([]string, map[string]bool) {
+	t := reflect.TypeOf(Translation{})
+	keys := make([]string, 0, t.NumField())
+	isMessage := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		keys = append(keys, t.Field(i).Name)
+		isMessage[t.Field(i).Name] = t.Field(i).Type == reflect.TypeOf(Message{})
+	}
+	return keys, isMessage
+}
+
+func decodeFile(fsys fs.FS, name string) (map[string]any, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	_, err = toml.Decode(string(data), &m)
+	return m, err
+}
+
+// messagePlaceholders returns the placeholders of a decoded Message
+func messagePlaceholders(v any) ([]string, bool) {
+	forms, err := messageForms(v)
+	if err != nil {
+		return nil, false
+	}
+	return placeholders(forms), true
+}
+
+// Check checks every .toml translation file of fsys against Translation and
+// against en.toml. Reports are sorted by file name, English first.
+func Check(fsys fs.FS) ([]*Report, error) {
+	names, err := fs.Glob(fsys, "*.toml")
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if a == b {
+			return 0
+		} else if a == "en.toml" {
+			return -1
+		} else if b == "en.toml" {
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	keys, isMessage := translationKeys()
+
+	en, err := decodeFile(fsys, "en.toml")
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*Report, 0, len(names))
+	for _, name := range names {
+		r := &Report{File: name}
+		reports = append(reports, r)
+
+		m, err := decodeFile(fsys, name)
+		if err != nil {
+			r.ParseErr = err
+			continue
+		}
+
+		for _, k := range keys {
+			v, ok := m[k]
+			if !ok {
+				r.Missing = append(r.Missing, k)
+				continue
+			}
+			if isMessage[k] {
+				p, ok := messagePlaceholders(v)
+				pEn, _ := messagePlaceholders(en[k])
+				if !ok || !slices.Equal(p, pEn) {
+					r.BadMessages = append(r.BadMessages, k)
+				}
+				continue
+			}
+			str, ok := v.(string)
+			enStr, _ := en[k].(string)
+			if !ok || !slices.Equal(formatVerbs(str), formatVerbs(enStr)) {
+				r.BadVerbs = append(r.BadVerbs, k)
+			}
+		}
+		for k := range m {
+			if !slices.Contains(keys, k) {
+				r.Unused = append(r.Unused, k)
+			}
+		}
+		slices.Sort(r.Unused)
+	}
+	return reports, nil
+}
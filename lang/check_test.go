@@ -0,0 +1,86 @@
+package lang
+
+import (
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// TestTranslations checks the embedded translation files, like langcheck
+// -strict: every language must define every key, so that no screen falls back
+// to English.
+func TestTranslations(t *testing.T) {
+	fsys, err := fs.Sub(translationFiles, "translations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reports, err := Check(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) == 0 {
+		t.Fatal("no translation file found")
+	}
+
+	for _, r := range reports {
+		if r.ParseErr != nil {
+			t.Errorf("%s: %v", r.File, r.ParseErr)
+		}
+		if len(r.Missing) > 0 {
+			t.Errorf("%s: missing keys %v", r.File, r.Missing)
+		}
+		if len(r.Unused) > 0 {
+			t.Errorf("%s: unused keys %v", r.File, r.Unused)
+		}
+		if len(r.BadVerbs) > 0 {
+			t.Errorf("%s: format verbs don't match English: %v", r.File, r.BadVerbs)
+		}
+		if len(r.BadMessages) > 0 {
+			t.Errorf("%s: placeholders don't match English: %v", r.File, r.BadMessages)
+		}
+	}
+	if reports[0].File != "en.toml" {
+		t.Errorf("first report is %s, want en.toml", reports[0].File)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.toml": {Data: []byte(`
+Ok = "Ok"
+ErrMinStakeAmount = "minimum stake amount is %v"
+ConfirmStake = "stake {amount}?"
+`)},
+		"xx.toml": {Data: []byte(`
+Sned = "typo"
+ErrMinStakeAmount = "minimum %v %v"
+ConfirmStake = "stake {amuont}?"
+`)},
+		"yy.toml": {Data: []byte(`Ok = "unterminated`)},
+	}
+	reports, err := Check(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 3 || reports[0].File != "en.toml" {
+		t.Fatalf("reports = %v, want en.toml first", reports)
+	}
+
+	xx := reports[1]
+	if !slices.Equal(xx.Unused, []string{"Sned"}) {
+		t.Errorf("unused = %v", xx.Unused)
+	}
+	if !slices.Equal(xx.BadVerbs, []string{"ErrMinStakeAmount"}) {
+		t.Errorf("bad verbs = %v", xx.BadVerbs)
+	}
+	if !slices.Equal(xx.BadMessages, []string{"ConfirmStake"}) {
+		t.Errorf("bad messages = %v", xx.BadMessages)
+	}
+	if !slices.Contains(xx.Missing, "Ok") {
+		t.Errorf("missing = %v, want Ok", xx.Missing)
+	}
+	if reports[2].ParseErr == nil || !reports[2].HasErrors() {
+		t.Error("invalid TOML not reported")
+	}
+}
@@ -0,0 +1,142 @@
+package lang
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+var (
+	ErrInvalidNumber = errors.New("invalid number")
+	// ErrAmbiguousNumber is returned for numbers like "1.500" in languages
+	// that group thousands with dots, as the user may have meant 1.5
+	ErrAmbiguousNumber = errors.New("ambiguous number")
+)
+
+// Tag returns the language of the translation
+func (t *Translation) Tag() language.Tag {
+	return t.tag
+}
+
+// setTag sets the language of the translation, and derives its number
+// separators from the CLDR data
+func (t *Translation) setTag(tag language.Tag) {
+	t.tag = tag
+
+	t.groupSep, t.decimalSep = ",", "."
+	s := message.NewPrinter(tag).Sprint(number.Decimal(1234.5, number.Scale(1)))
+	if i, j := strings.IndexRune(s, '1'), strings.IndexRune(s, '2'); i >= 0 && j > i {
+		t.groupSep = s[i+1 : j]
+	}
+	if i, j := strings.IndexRune(s, '4'), strings.IndexRune(s, '5'); i >= 0 && j > i {
+		t.decimalSep = s[i+1 : j]
+	}
+}
+
+// FormatDecimal localizes a decimal number like "-1234.5": the integer part is
+// grouped by thousands and the decimal separator of the language is used.
+func (t *Translation) FormatDecimal(s string) string {
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(t.groupSep)
+		}
+		b.WriteRune(c)
+	}
+	if hasFrac {
+		b.WriteString(t.decimalSep)
+		b.WriteString(fracPart)
+	}
+	return b.String()
+}
+
+// ParseDecimal is the inverse of FormatDecimal. It returns the number with a
+// "." decimal separator and no grouping. Thousands separators are only
+// accepted every three digits after a first group that doesn't start with 0,
+// so that "1.5" is never read as 15 in languages that group with dots. "." is
+// also accepted as the decimal separator when it is not the group separator
+// of the language. In the languages that group with dots, a number with a
+// single dot and no decimal separator, like "1.500", is rejected with
+// ErrAmbiguousNumber.
+func (t *Translation) ParseDecimal(s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	sign := ""
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, s = s[:1], s[1:]
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(s, t.decimalSep)
+	if !hasFrac && t.groupSep != "." {
+		intPart, fracPart, hasFrac = strings.Cut(s, ".")
+	}
+	if !hasFrac && t.groupSep == "." && strings.Count(intPart, ".") == 1 {
+		return "", ErrAmbiguousNumber
+	}
+
+	// spaces are common group separators, whatever the language
+	intPart = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\u00a0' || r == '\u202f' {
+			return '\u00a0'
+		}
+		return r
+	}, intPart)
+	if t.groupSep != "" && t.groupSep != "\u00a0" {
+		intPart = strings.ReplaceAll(intPart, t.groupSep, "\u00a0")
+	}
+	if strings.Contains(intPart, "\u00a0") {
+		groups := strings.Split(intPart, "\u00a0")
+		for i, g := range groups {
+			if (i == 0 && (g == "" || len(g) > 3 || g[0] == '0')) || (i > 0 && len(g) != 3) {
+				return "", ErrInvalidNumber
+			}
+		}
+		intPart = strings.Join(groups, "")
+	}
+
+	if intPart == "" && fracPart == "" {
+		return "", ErrInvalidNumber
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) || (hasFrac && fracPart == "") {
+		return "", ErrInvalidNumber
+	}
+
+	if intPart == "" {
+		intPart = "0"
+	}
+	if hasFrac {
+		return sign + intPart + "." + fracPart, nil
+	}
+	return sign + intPart, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatTime formats t in the local time zone with the date layout of the
+// language
+func (t *Translation) FormatTime(tm time.Time) string {
+	return tm.Local().Format(t.DateTimeFormat)
+}
+
+// FormatTimeSeconds is like FormatTime, with the seconds
+func (t *Translation) FormatTimeSeconds(tm time.Time) string {
+	return tm.Local().Format(t.DateTimeSecondsFormat)
+}
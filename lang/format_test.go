@@ -0,0 +1,88 @@
+package lang
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func translationFor(tag language.Tag) *Translation {
+	t := &Translation{}
+	t.setTag(tag)
+	return t
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		lang  language.Tag
+		input string
+		want  string
+		err   error
+	}{
+		{language.English, "1234.5", "1234.5", nil},
+		{language.English, "1,234.5", "1234.5", nil},
+		{language.English, "1,234,567", "1234567", nil},
+		{language.English, "1 234.5", "1234.5", nil},
+		{language.English, ".5", "0.5", nil},
+		{language.English, "-0.125", "-0.125", nil},
+		{language.English, "0.125", "0.125", nil},
+		{language.English, "1,5", "", ErrInvalidNumber},
+		{language.English, "0,125", "", ErrInvalidNumber},
+		{language.English, "01,234", "", ErrInvalidNumber},
+		{language.English, "1,2345", "", ErrInvalidNumber},
+		{language.English, ",123", "", ErrInvalidNumber},
+		{language.English, "5.", "", ErrInvalidNumber},
+		{language.English, "", "", ErrInvalidNumber},
+		{language.English, "1e5", "", ErrInvalidNumber},
+
+		{language.German, "1,5", "1.5", nil},
+		{language.German, "1.234,5", "1234.5", nil},
+		{language.German, "1.234.567", "1234567", nil},
+		{language.German, "1.234.567,89", "1234567.89", nil},
+		{language.German, "0,125", "0.125", nil},
+		{language.German, "1 234,5", "1234.5", nil},
+		{language.German, "1500", "1500", nil},
+		{language.German, "1.500", "", ErrAmbiguousNumber},
+		{language.German, "0.125", "", ErrAmbiguousNumber},
+		{language.German, "1.5", "", ErrAmbiguousNumber},
+		{language.German, "0.125,5", "", ErrInvalidNumber},
+		{language.German, "0.125.000", "", ErrInvalidNumber},
+		{language.German, "1.23.456", "", ErrInvalidNumber},
+
+		{language.Italian, "1.500", "", ErrAmbiguousNumber},
+		{language.Italian, "1.500,0", "1500.0", nil},
+		{language.Spanish, "0.125", "", ErrAmbiguousNumber},
+		{language.Spanish, "0,125", "0.125", nil},
+		{language.Portuguese, "1.500", "", ErrAmbiguousNumber},
+		{language.Portuguese, "1.234.567", "1234567", nil},
+
+		// "." is not a group separator in French, so it is read as decimals
+		{language.French, "1.5", "1.5", nil},
+		{language.French, "1,5", "1.5", nil},
+		{language.French, "1 234,5", "1234.5", nil},
+		{language.French, "0 125", "", ErrInvalidNumber},
+	}
+
+	for _, tt := range tests {
+		got, err := translationFor(tt.lang).ParseDecimal(tt.input)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("%v: ParseDecimal(%q) = %q, %v, want %q, %v", tt.lang, tt.input, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+// TestParseFormattedDecimal checks that the amounts shown by the wallet, which
+// always have decimals, can be pasted back
+func TestParseFormattedDecimal(t *testing.T) {
+	for _, tag := range []language.Tag{language.English, language.German, language.French, language.Russian} {
+		tr := translationFor(tag)
+		for _, v := range []string{"0.125", "1234.5", "1234567.00000001", "-1000.0", "999", "999999999.5"} {
+			s := tr.FormatDecimal(v)
+			got, err := tr.ParseDecimal(s)
+			if err != nil || got != v {
+				t.Errorf("%v: ParseDecimal(%q) = %q, %v, want %q", tag, s, got, err, v)
+			}
+		}
+	}
+}
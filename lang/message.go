@@ -0,0 +1,152 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// Args are the named arguments of a Message
+type Args map[string]any
+
+// Message is a translation with named placeholders, like "unlocks in {time}",
+// so that languages can reorder the arguments. It may have plural forms,
+// which are selected by the "count" argument. In the TOML files, a message is
+// either a string or a table of plural forms:
+//
+//	[StakedUnlock]
+//	one = "{count} block left"
+//	other = "{count} blocks left"
+//
+// The forms are the CLDR plural categories: zero, one, two, few, many and
+// other. "other" is required.
+type Message struct {
+	forms map[string]string
+	tag   language.Tag
+}
+
+var placeholderRegex = regexp.MustCompile(`\{[a-zA-Z_][a-zA-Z0-9_]*\}`)
+
+// pluralForms are the names of the plural.Form values
+var pluralForms = map[plural.Form]string{
+	plural.Other: "other",
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+}
+
+func isPluralForm(s string) bool {
+	for _, v := range pluralForms {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalTOML implements toml.Unmarshaler
+func (m *Message) UnmarshalTOML(v any) error {
+	forms, err := messageForms(v)
+	if err != nil {
+		return err
+	}
+	m.forms = forms
+	return nil
+}
+
+// messageForms returns the plural forms of a decoded TOML value
+func messageForms(v any) (map[string]string, error) {
+	switch v := v.(type) {
+	case string:
+		return map[string]string{"other": v}, nil
+	case map[string]any:
+		forms := make(map[string]string, len(v))
+		for k, f := range v {
+			if !isPluralForm(k) {
+				return nil, fmt.Errorf("unknown plural form %q", k)
+			}
+			s, ok := f.(string)
+			if !ok {
+				return nil, fmt.Errorf("plural form %q is not a string", k)
+			}
+			forms[k] = s
+		}
+		if _, ok := forms["other"]; !ok {
+			return nil, errors.New(`missing plural form "other"`)
+		}
+		return forms, nil
+	}
+	return nil, fmt.Errorf("invalid message type %T", v)
+}
+
+func (m Message) IsEmpty() bool {
+	return len(m.forms) == 0
+}
+
+// Format returns the message with the placeholders replaced by args.
+// Placeholders without an argument are left as they are.
+func (m Message) Format(args Args) string {
+	text := m.forms["other"]
+	if count, ok := args["count"]; ok {
+		if s, ok := m.forms[m.pluralForm(count)]; ok {
+			text = s
+		}
+	}
+
+	return placeholderRegex.ReplaceAllStringFunc(text, func(s string) string {
+		v, ok := args[s[1:len(s)-1]]
+		if !ok {
+			return s
+		}
+		return fmt.Sprint(v)
+	})
+}
+
+// pluralForm returns the plural category of count in the message language
+func (m Message) pluralForm(count any) string {
+	var n int
+	switch c := count.(type) {
+	case int:
+		n = c
+	case int64:
+		n = int(c)
+	case uint64:
+		n = int(min(c, uint64(^uint(0)>>1)))
+	case uint:
+		n = int(min(c, ^uint(0)>>1))
+	default:
+		var err error
+		n, err = strconv.Atoi(fmt.Sprint(count))
+		if err != nil {
+			return "other"
+		}
+	}
+	if n < 0 {
+		n = -n
+	}
+	return pluralForms[plural.Cardinal.MatchPlural(m.tag, n, 0, 0, 0, 0)]
+}
+
+// placeholders returns the names of the placeholders used by every form of
+// the message, sorted
+func placeholders(forms map[string]string) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, f := range forms {
+		for _, p := range placeholderRegex.FindAllString(f, -1) {
+			if !seen[p] {
+				seen[p] = true
+				names = append(names, p)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
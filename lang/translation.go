@@ -12,60 +12,92 @@ import (
 
 type Translation struct {
 	Language string
+	// DateTimeFormat is the Go time layout of the dates and times
+	DateTimeFormat string
+	// DateTimeSecondsFormat is DateTimeFormat with the seconds
+	DateTimeSecondsFormat string
 
-	Ok, Cancel, Back      string
-	Confirm               string
-	CreateWallet          string
-	OpenWallet            string
-	RestoreFromSeed       string
-	WalletName            string
-	ErrWalletNameTooLong  string
-	ErrWalletNameTooShort string
-	ErrWalletNameInvalid  string
-	ErrInvalidDelegateId  string
-	Password              string
-	PasswordTooShort      string
-	RepeatPassword        string
-	PasswordNotMatch      string
-	Seed                  string
-	DisplaySeed           string
-	TransferAmount        string
-	Recipient             string
-	Transfer              string
-	TabHome               string
-	TabTransfer           string
-	TabHistory            string
-	Settings              string
-	Balance               string
-	BalanceCopied         string
-	StakedBalance         string
-	StakedBalanceCopied   string
-	Address               string
-	AddressCopied         string
-	ConfirmTransfer       string
-	LoadingWallet         string
-	CreatingWallet        string
-	ViewSeed              string
-	InputPassword         string
-	YourSeedIs            string
-	StoreSeedSafely       string
-	UnderstandSeed        string
-	TabStaking            string
-	StakedUnlockHeight    string
-	StakedUnlockCopied    string
-	DelegateId            string
-	Copied                string
-	SetDelegate           string
-	SetDelegateConfirm    string
-	Stake                 string
-	Unstake               string
-	ConfirmStake          string
-	ConfirmUnstake        string
+	tag        language.Tag
+	groupSep   string
+	decimalSep string
+
+	Ok, Cancel, Back           string
+	Confirm                    string
+	CreateWallet               string
+	OpenWallet                 string
+	RestoreFromSeed            string
+	WalletName                 string
+	ErrWalletNameTooLong       string
+	ErrWalletNameTooLongStored string
+	ErrWalletNameTooShort      string
+	ErrInvalidDelegateId       string
+	Password                   string
+	PasswordTooShort           string
+	RepeatPassword             string
+	PasswordNotMatch           string
+	Seed                       string
+	DisplaySeed                string
+	TransferAmount             string
+	Recipient                  string
+	Transfer                   string
+	TabHome                    string
+	TabTransfer                string
+	TabHistory                 string
+	Settings                   string
+	Balance                    string
+	BalanceCopied              string
+	StakedBalance              string
+	StakedBalanceCopied        string
+	Address                    string
+	AddressCopied              string
+	ConfirmTransfer            string
+	LoadingWallet              string
+	CreatingWallet             string
+	ViewSeed                   string
+	InputPassword              string
+	YourSeedIs                 string
+	StoreSeedSafely            string
+	UnderstandSeed             string
+	TabStaking                 string
+	StakedUnlockHeight         Message
+	StakedUnlockCopied         string
+	DelegateId                 string
+	Copied                     string
+	SetDelegate                string
+	SetDelegateConfirm         string
+	Stake                      string
+	Unstake                    string
+	ConfirmStake               Message
+	ConfirmUnstake             Message
+
+	DelegateNone            string
+	DelegateHint            string
+	ErrMinStakeAmount       string
+	ErrInvalidUnstakeAmount string
+	ErrAmountZero           string
+	ErrTooManyDecimals      string
+	ErrAmbiguousAmount      string
+	FailedToCreateStakeTx   string
+
+	FailedToGetWalletList string
+	FailedToOpenWallet    string
+	FailedToCreateWallet  string
+	CannotCreateWallet    string
+	CannotRestoreWallet   string
+	WalletAlreadyExists   string
+
+	FailedToRenameWallet  string
+	FailedToDeleteWallet  string
+	FailedToRestoreWallet string
+	FailedToExportWallet  string
+	FailedToImportWallet  string
+	FailedToOpenBackup    string
 
 	TransferConfirm       string
 	ReviewTransferDetails string
-	TransferFields        string
+	TransferFields        Message
 	RecentTransactions    string
+	LoadingHistory        string
 
 	InvalidAmount    string
 	InvalidWallet    string
@@ -77,13 +109,87 @@ type Translation struct {
 	TXID  string
 	TxFee string
 
-	NodeAddress string
-	ChangeNode  string
+	NodeAddress       string
+	ChangeNode        string
+	NodeManager       string
+	NodeEnabled       string
+	NodeNotTested     string
+	AddNode           string
+	TestNode          string
+	TestAllNodes      string
+	Save              string
+	Latency           string
+	Height            string
+	LastError         string
+	ErrInvalidNodeUrl string
+	ErrInvalidNodePin string
+	ErrDuplicateNode  string
+	ErrNoNodeEnabled  string
+	NodeTls           string
+	NodeUntrusted     string
+	PinnedKey         string
+	PinnedKeyHint     string
+	CAFile            string
+	CAFileHint        string
+	NodeCredentials   string
+	Username          string
+	BearerToken       string
+	BearerTokenHint   string
+	CustomHeaders     string
+	CustomHeadersHint string
+
+	ImportWallet   string
+	ExportWallet   string
+	WalletExported string
+	SelectWallet   string
+
+	Backup           string
+	RestoreBackup    string
+	BackupsAvailable string
+
+	WalletDir       string
+	ChangeWalletDir string
+	ResetWalletDir  string
+
+	CloseWallet   string
+	SwitchWallet  string
+	ClosingWallet string
+
+	RenameWallet         string
+	DeleteWallet         string
+	DeleteWalletConfirm  string
+	TypeWalletName       string
+	ErrConfirmWalletName string
+	WalletMovedToTrash   string
+	Trash                string
+	TrashEmpty           string
+	Restore              string
+
+	NoWallets  string
+	LastOpened string
+	Never      string
 
-	StatusConnected string
-	StatusError     string
+	LanguageAutomatic string
+
+	Theme       string
+	ThemeSystem string
+	ThemeLight  string
+	ThemeDark   string
+
+	ProxySettings   string
+	ProxyUrl        string
+	ProxyHint       string
+	ErrInvalidProxy string
+
+	StatusConnected     string
+	StatusError         string
+	StatusNodeDisagrees string
+	StatusUntrusted     string
+	StatusOffline       string
+	RetryNow            string
 
 	Time          string
+	Pending       string
 	Confirmations string
 
 	UpdateGui      string
@@ -121,6 +227,19 @@ func init() {
 		if err != nil {
 			panic(err)
 		}
+
+		Lang[languageName].setTag(languageName)
+
+		// messages need the language for their plural rules
+		x := reflect.ValueOf(Lang[languageName]).Elem()
+		for i := 0; i < x.NumField(); i++ {
+			if !x.Field(i).CanSet() {
+				continue
+			}
+			if msg, ok := x.Field(i).Addr().Interface().(*Message); ok {
+				msg.tag = languageName
+			}
+		}
 	}
 
 	en := Lang[language.English]
@@ -134,9 +253,20 @@ func init() {
 		xnum := x.Elem().NumField()
 
 		for i := 0; i < xnum; i++ {
-			va := x.Elem().Field(i).String()
-			if va == "" {
-				x.Elem().Field(i).SetString(xEn.Elem().Field(i).String())
+			field := x.Elem().Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			switch va := field.Interface().(type) {
+			case string:
+				if va == "" {
+					field.SetString(xEn.Elem().Field(i).String())
+				}
+			case Message:
+				// the English message keeps the English plural rules
+				if va.IsEmpty() {
+					field.Set(xEn.Elem().Field(i))
+				}
 			}
 		}
 	}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/Xuanwo/go-locale"
+	"github.com/virel-project/virel-gui/v2/lang"
+	"github.com/virel-project/virel-gui/v2/save"
+	"golang.org/x/text/language"
+)
+
+// detectLanguage returns the translation matching the language of the system,
+// or of the browser on the web build
+func detectLanguage() *lang.Translation {
+	t := lang.Lang[language.English]
+
+	tags, err := locale.DetectAll()
+	if err != nil {
+		fmt.Println("could not detect language:", err)
+		tags = []language.Tag{}
+	}
+
+	fmt.Println("tags:", tags)
+
+	for _, v := range tags {
+		if lang.Lang[v] != nil {
+			fmt.Println("found language:", v)
+			t = lang.Lang[v]
+		} else if lang.Lang[v.Parent()] != nil {
+			fmt.Println("found language:", v.Parent())
+			t = lang.Lang[v.Parent()]
+		}
+	}
+
+	detectedLang, err := lang.DetectIETF()
+	if detectedLang == "" || err != nil {
+		fmt.Println("LANG environment variable is not set.", err)
+	}
+	fmt.Println("LANG is:", detectedLang)
+	if len(detectedLang) > 0 {
+		tag, err := language.Parse(detectedLang)
+		if err != nil {
+			fmt.Println("failed to parse language:", err)
+		} else {
+			t = lang.GetTranslation(tag)
+		}
+	}
+	return t
+}
+
+// loadLanguage returns the translation chosen by the user. An empty tag means
+// automatic detection.
+func loadLanguage(tag string) *lang.Translation {
+	if tag != "" {
+		t, err := language.Parse(tag)
+		if err == nil {
+			return lang.GetTranslation(t)
+		}
+		fmt.Println("invalid saved language:", tag, err)
+	}
+	return detectLanguage()
+}
+
+// setLanguage changes the language and saves the choice. An empty tag
+// restores automatic detection.
+func setLanguage(tag string) error {
+	T = loadLanguage(tag)
+	return save.UpdateSettings(func(s *save.Settings) {
+		s.Language = tag
+	})
+}
+
+// languageOptions returns the names of the available languages, sorted, and
+// their tags
+func languageOptions() ([]string, map[string]string) {
+	names := make([]string, 0, len(lang.Lang))
+	tags := make(map[string]string, len(lang.Lang))
+	for tag, v := range lang.Lang {
+		names = append(names, v.Language)
+		tags[v.Language] = tag.String()
+	}
+	slices.Sort(names)
+	return names, tags
+}
+
+// languageSelect lets the user choose the language, or automatic detection.
+// onChange is called after the language has changed.
+func languageSelect(onChange func()) *widget.Select {
+	savedLanguage := ""
+	if settings, err := save.LoadSettings(); err == nil {
+		savedLanguage = settings.Language
+	}
+
+	names, tags := languageOptions()
+	options := append([]string{T.LanguageAutomatic}, names...)
+
+	sel := widget.NewSelect(options, nil)
+	if savedLanguage == "" {
+		sel.SetSelected(T.LanguageAutomatic)
+	} else {
+		sel.SetSelected(T.Language)
+	}
+	sel.OnChanged = func(s string) {
+		tag := tags[s]
+		if tag == savedLanguage {
+			return
+		}
+		fmt.Println("language changed:", s)
+
+		err := setLanguage(tag)
+		if err != nil {
+			fmt.Println("failed to save language:", err)
+		}
+		onChange()
+	}
+	return sel
+}
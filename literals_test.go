@@ -0,0 +1,121 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// userFacing are the functions and methods whose arguments are shown to the
+// user. Methods are matched by name whatever their receiver.
+var userFacing = []string{
+	"ErrorDialog", "InfoDialog",
+	"dialog.NewError", "dialog.ShowError",
+	"dialog.NewInformation", "dialog.ShowInformation",
+	"dialog.NewConfirm", "dialog.ShowConfirm",
+	"dialog.NewCustom", "dialog.ShowCustom",
+	"dialog.NewCustomConfirm", "dialog.ShowCustomConfirm",
+	"dialog.NewForm", "dialog.ShowForm",
+	"widget.NewLabel", "widget.NewLabelWithStyle",
+	"widget.NewButton", "widget.NewButtonWithIcon",
+	"widget.NewCheck", "widget.NewHyperlink",
+	"widget.NewFormItem", "widget.NewRichTextFromMarkdown",
+	"container.NewTabItem", "container.NewTabItemWithIcon",
+	"fyne.NewMenu", "fyne.NewMenuItem",
+	"SetText", "SetPlaceHolder", "SetTitle", "NewWindow",
+}
+
+// allowedLiterals are the literals that may be shown as is, because they are
+// names or examples of the expected input rather than text
+var allowedLiterals = []string{
+	"Virel GUI v%d.%d.%d",
+	"# Virel GUI",
+	"https://node.example.org:443",
+	"socks5://127.0.0.1:9050",
+	"X-Api-Key: ...",
+}
+
+func callName(call *ast.CallExpr) string {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return fn.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := fn.X.(*ast.Ident); ok && slices.Contains(userFacing, pkg.Name+"."+fn.Sel.Name) {
+			return pkg.Name + "." + fn.Sel.Name
+		}
+		return fn.Sel.Name
+	}
+	return ""
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// hasText reports whether s contains words, as opposed to punctuation or
+// format verbs only
+func hasText(s string) bool {
+	s = formatVerb.ReplaceAllString(s, "")
+	return strings.IndexFunc(s, unicode.IsLetter) >= 0
+}
+
+// literalStrings returns the string literals of the expression that contain
+// text. Callbacks, nested user-facing calls and the placeholder names of
+// lang.Args are skipped, as they are checked on their own or never shown.
+func literalStrings(expr ast.Expr) []*ast.BasicLit {
+	lits := []*ast.BasicLit{}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch v := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			return !slices.Contains(userFacing, callName(v))
+		case *ast.KeyValueExpr:
+			lits = append(lits, literalStrings(v.Value)...)
+			return false
+		case *ast.BasicLit:
+			s, err := strconv.Unquote(v.Value)
+			if v.Kind == token.STRING && err == nil && hasText(s) && !slices.Contains(allowedLiterals, s) {
+				lits = append(lits, v)
+			}
+		}
+		return true
+	})
+	return lits
+}
+
+// TestNoLiteralUserStrings fails when a literal string is passed to a
+// function that shows it to the user. Such strings must be added to
+// lang.Translation instead.
+func TestNoLiteralUserStrings(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || !slices.Contains(userFacing, callName(call)) {
+					return true
+				}
+				for _, arg := range call.Args {
+					for _, lit := range literalStrings(arg) {
+						t.Errorf("%s: literal %s passed to %s, add it to lang.Translation",
+							fset.Position(lit.Pos()), lit.Value, callName(call))
+					}
+				}
+				return true
+			})
+		}
+	}
+}
@@ -1,28 +1,21 @@
 package main
 
 import (
-	"encoding/hex"
 	"errors"
+	"flag"
 	"fmt"
 	"net/url"
-	"os"
-	"regexp"
 	"runtime"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/virel-project/virel-gui/v2/lang"
 	"github.com/virel-project/virel-gui/v2/mycontainer"
-	"github.com/virel-project/virel-gui/v2/mylayout"
-	"github.com/virel-project/virel-gui/v2/mywidget"
 	"github.com/virel-project/virel-gui/v2/save"
 
-	"github.com/virel-project/virel-blockchain/v3/address"
-	"github.com/virel-project/virel-blockchain/v3/config"
 	"github.com/virel-project/virel-blockchain/v3/logger"
-	"github.com/virel-project/virel-blockchain/v3/transaction"
-	"github.com/virel-project/virel-blockchain/v3/util"
 	"github.com/virel-project/virel-blockchain/v3/util/updatechecker"
 	"github.com/virel-project/virel-blockchain/v3/wallet"
 
@@ -33,71 +26,97 @@ import (
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-	"github.com/Xuanwo/go-locale"
 	"golang.org/x/text/language"
 )
 
 const TICKER = "VRL"
 
+// NETWORK is the network the wallets are created for, recorded in their metadata
+const NETWORK = "mainnet"
+
 var log = logger.New()
 
 var T = lang.Lang[language.English]
 
 var nodeManager *NodeManger
 
-func init() {
-	tags, err := locale.DetectAll()
-	if err != nil {
-		fmt.Println("could not detect language:", err)
-		tags = []language.Tag{}
-	}
-
-	fmt.Println("tags:", tags)
+// setupNodes creates the node manager from the settings and applies the proxy
+func setupNodes(settings *save.Settings) {
+	// NewNodeManager falls back to RPC_URLS when no node is saved
+	nodeManager = NewNodeManager(settings.Nodes)
 
-	for _, v := range tags {
-		if lang.Lang[v] != nil {
-			fmt.Println("found language:", v)
-			T = lang.Lang[v]
-		} else if lang.Lang[v.Parent()] != nil {
-			fmt.Println("found language:", v.Parent())
-			T = lang.Lang[v.Parent()]
-		}
+	nodes := nodeManager.Nodes()
+	err := LoadNodeAuth(nodes)
+	if err != nil {
+		fmt.Println("failed to load node credentials:", err)
+	} else {
+		nodeManager.SetNodes(nodes)
 	}
 
-	rpcUrls, err := os.ReadFile("rpc-urls.txt")
+	err = SetProxy(settings.Proxy)
 	if err != nil {
-		rpcUrls = []byte(RPC_URLS)
+		fmt.Println("invalid proxy:", err)
 	}
-	nodeManager = NewNodeManager(string(rpcUrls))
 }
 
+// updateUrl is queried on startup to check for a new release
+const updateUrl = "https://api.github.com/repos/virel-project/virel-gui/releases/latest"
+
 const width_limit = 750
 
 var w fyne.Window
 var a fyne.App
 
 func main() {
-	detectedLang, err := lang.DetectIETF()
-	if detectedLang == "" || err != nil {
-		fmt.Println("LANG environment variable is not set.", err)
+	flag.Parse()
+	setupWalletDir()
+
+	err := save.MigrateSettings(".")
+	if err != nil {
+		fmt.Println("settings migration:", err)
 	}
-	fmt.Println("LANG is:", detectedLang)
-	if len(detectedLang) > 0 {
-		tag, err := language.Parse(detectedLang)
-		if err != nil {
-			fmt.Println("failed to parse language:", err)
-		}
-		T = lang.GetTranslation(tag)
+	settings, err := save.LoadSettings()
+	if err != nil {
+		fmt.Println("failed to load settings:", err)
+		settings = &save.Settings{}
 	}
-	//T = lang.Lang[language.]
+	setupNodes(settings)
+
+	// a saved language takes precedence over the detected one
+	T = loadLanguage(settings.Language)
 
 	a = app.New()
+	applyTheme(settings.Theme)
 	w = a.NewWindow(fmt.Sprintf("Virel GUI v%d.%d.%d", VERSION_MAJOR, VERSION_MINOR, VERSION_PATCH))
 
-	w.Resize(fyne.NewSize(800, 600))
+	if settings.Window.Width > 0 && settings.Window.Height > 0 {
+		w.Resize(fyne.NewSize(settings.Window.Width, settings.Window.Height))
+	} else {
+		w.Resize(fyne.NewSize(800, 600))
+	}
+	// the window is the browser tab on the web build
+	if runtime.GOOS != "js" {
+		w.SetOnClosed(func() {
+			size := w.Canvas().Size()
+			err := save.UpdateSettings(func(s *save.Settings) {
+				s.Window.Width = size.Width
+				s.Window.Height = size.Height
+			})
+			if err != nil {
+				fmt.Println("failed to save window size:", err)
+			}
+		})
+	}
+
+	nodeManager.Start()
 
 	pageHome()
 
+	if err := ProxyError(); err != nil {
+		ErrorDialog(w, fmt.Errorf(T.ErrInvalidProxy, err))
+		proxyDialog()
+	}
+
 	w.ShowAndRun()
 }
 
@@ -125,34 +144,14 @@ func pageHome() {
 
 	fr = mycontainer.NewLimiter(width_limit, 800, fr)
 
-	options := make([]string, len(lang.Lang))
-
-	for _, v := range lang.Lang {
-		options = append(options, v.Language)
-	}
-
-	chooseLanguage := widget.NewSelect(options, func(s string) {
-		if s == T.Language {
-			return
-		}
-		fmt.Println("language changed:", s)
-
-		for _, v := range lang.Lang {
-			if v.Language == s {
-				T = v
-				pageHome()
-			}
-		}
-
-	})
-	chooseLanguage.SetSelected(T.Language)
+	chooseLanguage := languageSelect(pageHome)
 
 	body := container.NewStack(fr, container.NewVBox(layout.NewSpacer(), container.NewHBox(chooseLanguage, layout.NewSpacer())))
 
 	w.SetContent(body)
 
 	go func() {
-		status, remoteVersion, err := updatechecker.CheckForUpdate("https://api.github.com/repos/virel-project/virel-gui/releases/latest",
+		status, remoteVersion, err := updatechecker.CheckForUpdate(updateUrl,
 			VERSION_MAJOR, VERSION_MINOR, VERSION_PATCH)
 		if err != nil || status == updatechecker.StatusError {
 			log.Err(err)
@@ -178,37 +177,88 @@ func pageOpen() {
 
 	walls, err := save.GetWallets()
 	if err != nil {
-		ErrorDialog(w, fmt.Errorf("failed to get wallet list: %w", err))
+		ErrorDialog(w, fmt.Errorf(T.FailedToGetWalletList, err))
 		return
 	}
 
-	walletName := widget.NewSelect(walls, func(s string) {})
+	metas := make(map[string]*save.WalletMeta, len(walls))
+	for _, v := range walls {
+		meta, err := save.ReadWalletMeta(v)
+		if err != nil {
+			fmt.Println("failed to read wallet metadata:", v, err)
+			meta = &save.WalletMeta{Name: v}
+		}
+		metas[v] = meta
+	}
+	// most recently opened first
+	slices.SortFunc(walls, func(a, b string) int {
+		if c := metas[b].LastOpened.Compare(metas[a].LastOpened); c != 0 {
+			return c
+		}
+		return strings.Compare(metas[a].Name, metas[b].Name)
+	})
+
+	selected := ""
+
+	walletList := widget.NewList(func() int {
+		return len(walls)
+	}, func() fyne.CanvasObject {
+		name := widget.NewLabel("")
+		name.TextStyle.Bold = true
+		name.Truncation = fyne.TextTruncateEllipsis
+		details := widget.NewLabel("")
+		details.Truncation = fyne.TextTruncateEllipsis
+		return container.NewVBox(name, details)
+	}, func(id widget.ListItemID, o fyne.CanvasObject) {
+		meta := metas[walls[id]]
+		labels := o.(*fyne.Container).Objects
+		labels[0].(*widget.Label).SetText(meta.Name)
+		labels[1].(*widget.Label).SetText(walletMetaText(meta))
+	})
+	walletList.OnSelected = func(id widget.ListItemID) {
+		selected = walls[id]
+	}
+	walletList.OnUnselected = func(id widget.ListItemID) {
+		selected = ""
+	}
+	if len(walls) > 0 {
+		walletList.Select(0)
+	}
+
+	var listObj fyne.CanvasObject = walletList
+	if len(walls) == 0 {
+		listObj = container.NewCenter(widget.NewLabel(T.NoWallets))
+	}
+
 	walletPass := widget.NewPasswordEntry()
 
-	form := widget.NewForm(widget.NewFormItem(T.WalletName, walletName),
-		widget.NewFormItem(T.Password, walletPass))
+	form := widget.NewForm(widget.NewFormItem(T.Password, walletPass))
 
 	form.SubmitText = T.OpenWallet
 	form.CancelText = T.Cancel
 	form.OnSubmit = func() {
+		if selected == "" {
+			ErrorDialog(w, errors.New(T.SelectWallet))
+			return
+		}
 		loadingPage(T.LoadingWallet)
 		go func() {
-			ci := walletName.Selected
+			ci := selected
 			fileContent, err := save.ReadWallet(ci)
 			if err != nil {
-				ErrorDialog(w, fmt.Errorf("failed to open wallet: %w", err))
+				ErrorDialog(w, fmt.Errorf(T.FailedToOpenWallet, err))
 				pageOpen()
 				return
 			}
 
-			wall, err := wallet.OpenWallet(nodeManager.Urls()[0], fileContent, walletPass.Text)
+			wall, err := wallet.OpenWallet(nodeManager.Best(), fileContent, walletPass.Text)
 			if err != nil {
-				ErrorDialog(w, fmt.Errorf("failed to open wallet: %w", err))
 				pageOpen()
+				openFailedDialog(ci, walletPass.Text, fmt.Errorf(T.FailedToOpenWallet, err))
 				return
 			}
 
-			pageWallet(wall)
+			pageWallet(wall, ci)
 		}()
 
 	}
@@ -216,13 +266,70 @@ func pageOpen() {
 		pageHome()
 	}
 
+	// withSelected wraps an action that needs a wallet to be selected
+	withSelected := func(f func(name string)) func() {
+		return func() {
+			if selected == "" {
+				ErrorDialog(w, errors.New(T.SelectWallet))
+				return
+			}
+			f(selected)
+		}
+	}
+
+	exportBtn := widget.NewButtonWithIcon(T.ExportWallet, theme.UploadIcon(), withSelected(exportWallet))
+	importBtn := widget.NewButtonWithIcon(T.ImportWallet, theme.DownloadIcon(), func() {
+		importFile(importWalletDialog)
+	})
+
+	renameBtn := widget.NewButtonWithIcon(T.RenameWallet, theme.DocumentCreateIcon(), withSelected(renameWalletDialog))
+	deleteBtn := widget.NewButtonWithIcon(T.DeleteWallet, theme.DeleteIcon(), withSelected(deleteWalletDialog))
+	trashBtn := widget.NewButtonWithIcon(T.Trash, theme.HistoryIcon(), trashDialog)
+
+	bottom := container.NewVBox(form,
+		container.NewGridWithColumns(2, importBtn, exportBtn),
+		container.NewGridWithColumns(3, renameBtn, deleteBtn, trashBtn),
+	)
+
+	// wallets are stored in the browser on the web build
+	if runtime.GOOS != "js" {
+		bottom.Add(widget.NewButtonWithIcon(T.WalletDir, theme.FolderIcon(), func() {
+			walletDirDialog(pageOpen)
+		}))
+	}
+
 	fyne.Do(func() {
-		w.SetContent(mycontainer.NewWidthLimiter(width_limit, container.NewVBox(
-			title, form,
-		)))
+		w.SetContent(mycontainer.NewWidthLimiter(width_limit, container.NewBorder(title, bottom, nil, nil, listObj)))
 	})
 }
 
+// walletMetaText is the summary shown under the wallet name in pageOpen
+func walletMetaText(meta *save.WalletMeta) string {
+	lastOpened := T.Never
+	if !meta.LastOpened.IsZero() {
+		lastOpened = T.FormatTime(meta.LastOpened)
+	}
+	txt := T.LastOpened + ": " + lastOpened
+	if !meta.LastOpened.IsZero() {
+		txt += "   " + T.Balance + ": " + formatCoin(meta.LastBalance) + " " + TICKER
+	}
+	if meta.Network != "" {
+		txt += "   " + meta.Network
+	}
+	return txt
+}
+
+func validateWalletName(name string) error {
+	if utf8.RuneCountInString(name) > 50 {
+		return errors.New(T.ErrWalletNameTooLong)
+	} else if len(name) < 1 {
+		return errors.New(T.ErrWalletNameTooShort)
+	} else if len(save.WalletFileName(name)) > save.MaxWalletFileName {
+		return errors.New(T.ErrWalletNameTooLongStored)
+	}
+	return nil
+}
+
 func pageCreate() {
 	title := widget.NewRichTextFromMarkdown("## " + T.CreateWallet)
 
@@ -246,17 +353,13 @@ func pageCreate() {
 			err = errors.New(T.PasswordNotMatch)
 		} else if len(walletPass.Text) < 5 {
 			err = errors.New(T.PasswordTooShort)
-		} else if len(walletName.Text) > 50 {
-			err = errors.New(T.ErrWalletNameTooLong)
-		} else if len(walletName.Text) < 1 {
-			err = errors.New(T.ErrWalletNameTooShort)
-		} else if strings.Contains(walletName.Text, ".") {
-			err = errors.New(T.ErrWalletNameInvalid)
+		} else {
+			err = validateWalletName(walletName.Text)
 		}
-		wallName := url.PathEscape(walletName.Text)
+		wallName := save.WalletFileName(walletName.Text)
 
 		if err != nil {
-			ErrorDialog(w, fmt.Errorf("cannot create wallet: %w", err))
+			ErrorDialog(w, fmt.Errorf(T.CannotCreateWallet, err))
 			walletPass.SetText("")
 			walletPass2.SetText("")
 			return
@@ -268,19 +371,23 @@ func pageCreate() {
 
 			_, err := save.ReadWallet(wallName)
 			if err == nil {
-				ErrorDialog(w, fmt.Errorf("wallet %v already exists", wallName))
+				ErrorDialog(w, fmt.Errorf(T.WalletAlreadyExists, walletName.Text))
 				pageOpen()
 				return
 			}
 
-			wall, db, err := wallet.CreateWallet(nodeManager.Urls()[0], walletPass.Text, runtime.GOOS == "js")
+			wall, db, err := wallet.CreateWallet(nodeManager.Best(), walletPass.Text, runtime.GOOS == "js")
 			if err != nil {
-				ErrorDialog(w, fmt.Errorf("failed to create wallet: %w", err))
+				ErrorDialog(w, fmt.Errorf(T.FailedToCreateWallet, err))
 			}
 
 			save.SaveWallet(wallName, db)
+			err = save.SaveWalletMeta(wallName, newWalletMeta(walletName.Text))
+			if err != nil {
+				fmt.Println("failed to save wallet metadata:", err)
+			}
 
-			pageWallet(wall)
+			pageWallet(wall, wallName)
 			displaySeedDialog(wall)
 		}()
 
@@ -309,19 +416,25 @@ func pageRestore() {
 
 	form.SubmitText = T.RestoreFromSeed
 	form.OnSubmit = func() {
+		err := validateWalletName(walletName.Text)
+		if err != nil {
+			ErrorDialog(w, fmt.Errorf(T.CannotRestoreWallet, err))
+			return
+		}
+
 		loadingPage(T.LoadingWallet)
 		go func() {
-			filename := url.PathEscape(walletName.Text)
+			filename := save.WalletFileName(walletName.Text)
 
 			_, err := save.ReadWallet(filename)
 			if err == nil {
-				ErrorDialog(w, fmt.Errorf("wallet %v already exists", filename))
+				ErrorDialog(w, fmt.Errorf(T.WalletAlreadyExists, walletName.Text))
 				pageOpen()
 				return
 			}
-			wall, db, err := wallet.CreateWalletFromMnemonic(nodeManager.Urls()[0], walletSeed.Text, walletPass.Text, runtime.GOOS == "js")
+			wall, db, err := wallet.CreateWalletFromMnemonic(nodeManager.Best(), walletSeed.Text, walletPass.Text, runtime.GOOS == "js")
 			if err != nil {
-				ErrorDialog(w, fmt.Errorf("failed to open wallet: %w", err))
+				ErrorDialog(w, fmt.Errorf(T.FailedToOpenWallet, err))
 				pageRestore()
 				return
 			}
@@ -332,8 +445,12 @@ func pageRestore() {
 				pageRestore()
 				return
 			}
+			err = save.SaveWalletMeta(filename, newWalletMeta(walletName.Text))
+			if err != nil {
+				fmt.Println("failed to save wallet metadata:", err)
+			}
 
-			pageWallet(wall)
+			pageWallet(wall, filename)
 		}()
 
 	}
@@ -342,305 +459,19 @@ func pageRestore() {
 
 }
 
+func newWalletMeta(displayName string) *save.WalletMeta {
+	return &save.WalletMeta{
+		Name:    displayName,
+		Created: time.Now(),
+		Network: NETWORK,
+	}
+}
+
 func loadingPage(txt string) {
 	fmt.Println("loadingPage", txt)
 	w.SetContent(container.NewCenter(widget.NewRichTextFromMarkdown("## " + txt)))
 }
 
-var numRegex = regexp.MustCompile(`^([0-9]*[.])?[0-9]+$`)
-
-func pageWallet(wall *wallet.Wallet) {
-	yourBalance := mywidget.NewCard(a, theme.Color(theme.ColorNamePrimary),
-		util.FormatCoin(wall.GetBalance()), T.Balance, T.BalanceCopied)
-	stakedBalance := mywidget.NewCard(a, theme.Color(theme.ColorNameButton),
-		util.FormatCoin(wall.GetStakedBalance()), T.StakedBalance, T.StakedBalanceCopied)
-	yourAddress := mywidget.NewCard(a, theme.Color(theme.ColorNameButton), wall.GetAddress().String(),
-		T.Address, T.AddressCopied)
-
-	cardsGrid := container.New(mylayout.NewWrapLayout(800),
-		yourBalance, stakedBalance, yourAddress)
-
-	myWallet := container.NewPadded(container.NewVBox(
-		cardsGrid,
-		layout.NewSpacer(),
-		// widget.NewLabel(T.RecentTransactions),
-		//list,
-	))
-
-	recipient := widget.NewEntry()
-	recipient.Validator = func(s string) error {
-		if len(s) < 5 {
-			return errors.New(T.InvalidWallet)
-		}
-		_, err := address.FromString(s)
-		if err != nil {
-			return errors.New(T.InvalidWallet)
-		}
-		return nil
-	}
-
-	amount := widget.NewEntry()
-	amount.Validator = func(s string) error {
-		if !numRegex.MatchString(s) {
-			return errors.New(T.InvalidAmount)
-		}
-		return nil
-	}
-
-	recipient.Enable()
-	amount.Enable()
-
-	sendForm := &widget.Form{
-		Items: []*widget.FormItem{ // we can specify items in the constructor
-			{Text: T.Recipient, Widget: recipient},
-			{Text: T.TransferAmount, Widget: amount},
-		},
-
-		SubmitText: T.Transfer,
-	}
-	sendForm.OnSubmit = func() { // optional, handle form submission
-
-		log.Info("Form submitted:", recipient.Text)
-		log.Info("amount:", amount.Text)
-
-		walletPass := widget.NewEntry()
-		walletPass.Password = true
-		walletPass.Validator = func(s string) error {
-			if len(s) == 0 {
-				return errors.New(T.FieldRequired)
-			}
-			return nil
-		}
-
-		dlg := dialog.NewCustomConfirm(T.TransferConfirm, T.Confirm, T.Cancel, container.NewVBox(
-			widget.NewLabel(T.ReviewTransferDetails),
-			widget.NewLabel(
-				fmt.Sprintf(T.TransferFields, amount.Text, recipient.Text),
-			),
-			widget.NewForm(widget.NewFormItem(T.Password, walletPass)),
-		), func(ok bool) {
-			if !ok {
-				return
-			}
-
-			if walletPass.Text != string(wall.GetPassword()) {
-				ErrorDialog(w, errors.New(T.PasswordNotMatch))
-				return
-			}
-
-			amt, err := strconv.ParseFloat(amount.Text, 64)
-			if err != nil {
-				ErrorDialog(w, errors.New(T.InvalidAmount))
-				return
-			}
-			if amt <= 0 {
-				ErrorDialog(w, errors.New(T.InvalidAmount))
-				return
-			}
-
-			recv, err := address.FromString(recipient.Text)
-			if err != nil {
-				ErrorDialog(w, errors.New(T.InvalidWallet))
-				return
-			}
-
-			amtInt := uint64(amt * config.COIN)
-
-			txn, err := wall.Transfer([]transaction.Output{
-				{
-					Amount:    amtInt,
-					Recipient: recv.Addr,
-					PaymentId: recv.PaymentId,
-				},
-			}, true)
-			if err != nil {
-				ErrorDialog(w, fmt.Errorf(T.FailedToCreateTx, err))
-				return
-			}
-
-			dialog.NewCustomConfirm(T.ConfirmTransfer, T.Confirm, T.Cancel, container.NewVBox(
-				widget.NewLabel(T.Recipient+": "+recv.String()),
-				widget.NewLabel(T.TransferAmount+": "+util.FormatCoin(amtInt)+" "+TICKER),
-				widget.NewLabel(T.TxFee+": "+util.FormatCoin(txn.Fee)+" "+TICKER),
-				widget.NewLabel(T.TXID+": "+txn.Hash().String()),
-			), func(ok bool) {
-				if !ok {
-					return
-				}
-
-				res, err := wall.SubmitTx(txn)
-				if err != nil {
-					ErrorDialog(w, fmt.Errorf(T.FailedToSubmitTx, err))
-					return
-				}
-
-				InfoDialog(w, T.TransferSuccess, T.TXID+": "+res.TXID.String())
-
-			}, w).Show()
-
-		}, w)
-
-		dlg.Show()
-
-	}
-
-	sendTitle := NewTitle(T.Transfer)
-	mySend := container.NewVBox(sendTitle, sendForm)
-
-	myStaking := CreateStakingTab(wall)
-
-	txlist := TxList{
-		List: make([]HistoryObject, 0),
-	}
-
-	const time_format = "2006-01-02 15:04"
-
-	historyList := widget.NewTableWithHeaders(
-		func() (int, int) {
-			return len(txlist.List), 4
-		},
-		func() fyne.CanvasObject {
-			return widget.NewLabel(hex.EncodeToString(make([]byte, 32)))
-		},
-		func(i widget.TableCellID, co fyne.CanvasObject) {
-			//lbl := co.(*fyne.Container).Objects[0].(*widget.Label)
-			lbl := co.(*widget.Label)
-
-			x := txlist.List[i.Row]
-
-			switch i.Col {
-			case 0: // time
-				lbl.SetText(x.Time.Format(time_format))
-			case 1: // txid
-				lbl.SetText(x.TXID)
-			case 2: // amount
-				amtStr := strconv.FormatFloat(x.Amount, 'f', int(config.ATOMIC), 64)
-				if amtStr[0] != '-' {
-					amtStr = "+" + amtStr
-				}
-				lbl.SetText(amtStr)
-			case 3: // confs
-				lbl.SetText(strconv.FormatUint(wall.GetHeight()-x.Height, 10))
-			}
-		},
-	)
-	historyList.ShowHeaderColumn = false
-	historyList.ShowHeaderRow = true
-
-	timeWidth := widget.NewLabel(time_format).MinSize().Width
-	amtWidth := widget.NewLabel("+100.000000000").MinSize().Width
-
-	historyList.SetColumnWidth(0, timeWidth)
-	historyList.SetColumnWidth(2, amtWidth)
-	historyList.SetColumnWidth(3, timeWidth)
-
-	historyList.CreateHeader = func() fyne.CanvasObject {
-		lbl := widget.NewLabel("")
-		lbl.Truncation = fyne.TextTruncateEllipsis
-		return lbl
-	}
-	historyList.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
-		lbl := template.(*widget.Label)
-
-		switch id.Col {
-		case 0:
-			lbl.SetText(T.Time)
-		case 1:
-			lbl.SetText(T.TXID)
-		case 2:
-			lbl.SetText(T.TransferAmount)
-		case 3:
-			lbl.SetText(T.Confirmations)
-		}
-	}
-
-	seedBtn := widget.NewButton(T.DisplaySeed, func() {
-		passEntry := widget.NewEntry()
-		passEntry.Password = true
-
-		content := container.NewVBox(passEntry)
-
-		d := dialog.NewCustomConfirm(T.InputPassword, T.ViewSeed, T.Cancel, content, func(confirm bool) {
-			if !confirm {
-				return
-			}
-
-			if passEntry.Text != string(wall.GetPassword()) {
-				ErrorDialog(w, errors.New(T.PasswordNotMatch))
-				return
-			}
-
-			displaySeedDialog(wall)
-		}, w)
-		d.Show()
-	})
-
-	nodeLbl := widget.NewLabel(T.NodeAddress + ": " + wall.GetRpcDaemonAddress())
-
-	changeNodeBtn := widget.NewButton(T.ChangeNode, func() {
-		nodeUrlInput := widget.NewEntry()
-		nodeUrlInput.SetText(strings.Join(nodeManager.rpcUrls, ";"))
-
-		Dialog(w, T.ChangeNode, T.Ok, T.Cancel, nodeUrlInput, func(b bool) {
-			if !b {
-				return
-			}
-			nodeManager.rpcUrls = strings.Split(nodeUrlInput.Text, ";")
-			if len(nodeManager.rpcUrls) > 0 {
-				a := nodeManager.rpcUrls[0]
-				wall.SetRpcDaemonAddress(a)
-			}
-			save.SaveRpcUrls([]byte(nodeUrlInput.Text))
-			nodeLbl.SetText(T.NodeAddress + ": " + wall.GetRpcDaemonAddress())
-		})
-	})
-
-	settingsCont := container.NewVBox(NewTitle(T.Settings), seedBtn, nodeLbl, changeNodeBtn)
-
-	tabs := container.NewAppTabs(
-		container.NewTabItemWithIcon(T.TabHome, theme.HomeIcon(), myWallet),
-		container.NewTabItemWithIcon(T.TabTransfer, theme.MailSendIcon(), mySend),
-		container.NewTabItemWithIcon(T.TabHistory, theme.HistoryIcon(), historyList),
-		container.NewTabItemWithIcon(T.TabStaking, theme.StorageIcon(), myStaking.Container()),
-		container.NewTabItemWithIcon(T.Settings, theme.SettingsIcon(), settingsCont),
-	)
-
-	statusLabel := widget.NewLabel(T.StatusConnected)
-	statusBar := mywidget.NewBar(theme.Color(theme.ColorNameHeaderBackground), statusLabel)
-
-	go func() {
-		for {
-			err := nodeManager.Refresh(wall)
-			fyne.Do(func() {
-				if err != nil {
-					statusLabel.SetText(T.StatusError)
-				} else {
-					statusLabel.SetText(T.StatusConnected + " " + wall.GetRpcDaemonAddress())
-				}
-
-				yourBalance.SetTitle(util.FormatCoin(wall.GetBalance()))
-				stakedBalance.SetTitle(util.FormatCoin(wall.GetStakedBalance()))
-			})
-			if err != nil {
-				fmt.Println("failed to refresh:", err)
-				time.Sleep(10 * time.Second)
-				continue
-			}
-			err = txlist.Refresh(wall)
-			if err != nil {
-				fmt.Println("error fetching tx list:", err)
-			}
-			myStaking.Update()
-
-			time.Sleep(10 * time.Second)
-		}
-	}()
-
-	fyne.DoAndWait(func() {
-		w.SetContent(container.NewBorder(nil, statusBar, nil, nil, tabs))
-	})
-}
-
 func displaySeedDialog(wall *wallet.Wallet) {
 	confirmBtn := widget.NewButton(T.Confirm, nil)
 	confirmBtn.Disable()
@@ -0,0 +1,33 @@
+//go:build !js
+// +build !js
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+// Every valid name must be usable as a file name
+func TestValidWalletNamesCanBeStored(t *testing.T) {
+	store := &save.FileStore{Dir: t.TempDir()}
+	save.UseStore(store)
+
+	for _, name := range []string{strings.Repeat(".", 50), strings.Repeat("😀", 16), strings.Repeat("钱", 22)} {
+		if err := validateWalletName(name); err != nil {
+			t.Fatal(err)
+		}
+		fileName := save.WalletFileName(name)
+		if err := save.SaveWallet(fileName, []byte("x")); err != nil {
+			t.Errorf("saving wallet %q: %v", name, err)
+		}
+		if err := save.SaveWalletMeta(fileName, &save.WalletMeta{Name: name}); err != nil {
+			t.Errorf("saving metadata of wallet %q: %v", name, err)
+		}
+		if err := save.DeleteWallet(fileName); err != nil {
+			t.Errorf("deleting wallet %q: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWalletName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"", false},
+		{"wallet", true},
+		{strings.Repeat("a", 50), true},
+		{strings.Repeat("a", 51), false},
+		{strings.Repeat(".", 50), true},
+		{strings.Repeat("é", 33), true},
+		{strings.Repeat("😀", 16), true},
+		{strings.Repeat("😀", 17), false},
+		{strings.Repeat("😀", 50), false},
+		{strings.Repeat("钱", 22), true},
+		{strings.Repeat("钱", 30), false},
+	}
+	for _, tt := range tests {
+		err := validateWalletName(tt.name)
+		if (err == nil) != tt.valid {
+			t.Errorf("validateWalletName(%q) = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
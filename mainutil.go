@@ -35,3 +35,8 @@ func NewTitle(t string) *canvas.Text {
 
 	return title
 }
+
+// formatCoin formats an atomic amount with the number format of the language
+func formatCoin(n uint64) string {
+	return T.FormatDecimal(formatAtomic(n))
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+// NodeAuth holds the optional credentials sent to a node on every request
+type NodeAuth struct {
+	Username string            `json:"username,omitempty"`
+	Password string            `json:"password,omitempty"`
+	Token    string            `json:"token,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+func (a *NodeAuth) IsEmpty() bool {
+	return a == nil || (a.Username == "" && a.Password == "" && a.Token == "" && len(a.Headers) == 0)
+}
+
+// apply adds the credentials to the request headers
+func (a *NodeAuth) apply(req *http.Request) {
+	for k, v := range a.Headers {
+		req.Header.Set(k, v)
+	}
+	if a.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	} else if a.Username != "" || a.Password != "" {
+		req.SetBasicAuth(a.Username, a.Password)
+	}
+}
+
+// ParseHeaders parses one "Name: value" header per line
+func ParseHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" || strings.ContainsAny(k, " \t") {
+			return nil, errors.New("invalid header: " + line)
+		}
+		headers[http.CanonicalHeaderKey(k)] = strings.TrimSpace(v)
+	}
+	return headers, nil
+}
+
+// FormatHeaders is the inverse of ParseHeaders
+func FormatHeaders(headers map[string]string) string {
+	lines := make([]string, 0, len(headers))
+	for k, v := range headers {
+		lines = append(lines, k+": "+v)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// authTransport adds the node credentials to the requests sent to the nodes.
+// Credentials are matched by scheme and host so they are never sent elsewhere.
+type authTransport struct {
+	base http.RoundTripper
+}
+
+var authMut sync.RWMutex
+var authByOrigin = map[string]*NodeAuth{}
+
+func init() {
+	http.DefaultTransport = &authTransport{base: tlsTransport{}}
+}
+
+func origin(u *url.URL) string {
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authMut.RLock()
+	auth := authByOrigin[origin(req.URL)]
+	authMut.RUnlock()
+
+	if auth != nil {
+		// RoundTrippers must not modify the original request
+		req = req.Clone(req.Context())
+		auth.apply(req)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// applyNodeAuth replaces the credentials with the ones of the given nodes
+func applyNodeAuth(nodes []NodeConfig) {
+	byOrigin := map[string]*NodeAuth{}
+	for _, v := range nodes {
+		if v.Auth.IsEmpty() {
+			continue
+		}
+		u, err := url.Parse(v.Url)
+		if err != nil {
+			continue
+		}
+		byOrigin[origin(u)] = v.Auth
+	}
+
+	authMut.Lock()
+	authByOrigin = byOrigin
+	authMut.Unlock()
+}
+
+// The node credentials are kept out of the plain-text node list, encrypted with
+// AES-GCM under a random key that is generated on first use.
+//
+// The key is saved next to the credentials, in the settings directory or the
+// browser storage. This keeps the credentials out of settings.toml, which may
+// be shared or backed up on its own, but anyone who can read the settings
+// storage can decrypt them: they are not protected from other programs running
+// as the same user.
+func nodeAuthKey() ([]byte, error) {
+	key, err := save.ReadNodeAuthKey()
+	if err == nil {
+		if len(key) != 32 {
+			return nil, errors.New("invalid node credentials key")
+		}
+		return key, nil
+	}
+	// any other error must not replace the key, which would make the saved
+	// credentials unreadable
+	if !errors.Is(err, save.ErrNotFound) {
+		return nil, err
+	}
+
+	key = make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	return key, save.SaveNodeAuthKey(key)
+}
+
+func nodeAuthCipher() (cipher.AEAD, error) {
+	key, err := nodeAuthKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SaveNodeAuth encrypts and stores the credentials of the given nodes
+func SaveNodeAuth(nodes []NodeConfig) error {
+	byUrl := map[string]*NodeAuth{}
+	for _, v := range nodes {
+		if !v.Auth.IsEmpty() {
+			byUrl[v.Url] = v.Auth
+		}
+	}
+	data, err := json.Marshal(byUrl)
+	if err != nil {
+		return err
+	}
+
+	aead, err := nodeAuthCipher()
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	sealed := aead.Seal(nonce, nonce, data, nil)
+	return save.SaveNodeAuth([]byte(base64.StdEncoding.EncodeToString(sealed)))
+}
+
+// LoadNodeAuth decrypts the stored credentials and attaches them to the nodes
+func LoadNodeAuth(nodes []NodeConfig) error {
+	encoded, err := save.ReadNodeAuth()
+	if errors.Is(err, save.ErrNotFound) {
+		// no credentials have been saved yet
+		return nil
+	} else if err != nil {
+		return err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return err
+	}
+
+	aead, err := nodeAuthCipher()
+	if err != nil {
+		return err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return errors.New("node credentials are corrupted")
+	}
+	data, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return err
+	}
+
+	byUrl := map[string]*NodeAuth{}
+	err = json.Unmarshal(data, &byUrl)
+	if err != nil {
+		return err
+	}
+	for i, v := range nodes {
+		nodes[i].Auth = byUrl[v.Url]
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+// failingStore is a Store whose reads of one key fail
+type failingStore struct {
+	*save.MemStore
+	key string
+}
+
+func (s failingStore) Get(key string) ([]byte, error) {
+	if key == s.key {
+		return nil, errors.New("permission denied")
+	}
+	return s.MemStore.Get(key)
+}
+
+func TestNodeAuthRoundTrip(t *testing.T) {
+	save.UseStore(save.NewMemStore())
+
+	auth := &NodeAuth{Username: "user", Password: "secret", Headers: map[string]string{"X-Api-Key": "k"}}
+	err := SaveNodeAuth([]NodeConfig{
+		{Url: "https://a.invalid", Auth: auth},
+		{Url: "https://b.invalid"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := []NodeConfig{{Url: "https://a.invalid"}, {Url: "https://b.invalid"}}
+	err = LoadNodeAuth(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodes[0].Auth == nil || nodes[0].Auth.Password != "secret" || nodes[0].Auth.Headers["X-Api-Key"] != "k" {
+		t.Errorf("credentials of a = %+v", nodes[0].Auth)
+	}
+	if nodes[1].Auth != nil {
+		t.Errorf("credentials of b = %+v, want none", nodes[1].Auth)
+	}
+}
+
+func TestNodeAuthKeyIsNotReplacedOnReadError(t *testing.T) {
+	mem := save.NewMemStore()
+	save.UseStore(mem)
+
+	err := SaveNodeAuth([]NodeConfig{{Url: "https://a.invalid", Auth: &NodeAuth{Token: "t"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := save.ReadNodeAuthKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	save.UseStore(failingStore{mem, "node-auth.key"})
+	if err := LoadNodeAuth([]NodeConfig{{Url: "https://a.invalid"}}); err == nil {
+		t.Error("LoadNodeAuth succeeded without its key")
+	}
+	if err := SaveNodeAuth(nil); err == nil {
+		t.Error("SaveNodeAuth succeeded without its key")
+	}
+
+	save.UseStore(mem)
+	after, err := save.ReadNodeAuthKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(key) {
+		t.Fatal("the key was replaced after a read error")
+	}
+	nodes := []NodeConfig{{Url: "https://a.invalid"}}
+	if err := LoadNodeAuth(nodes); err != nil || nodes[0].Auth.Token != "t" {
+		t.Errorf("credentials lost: %v %+v", err, nodes[0].Auth)
+	}
+}
@@ -1,64 +1,375 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/virel-project/virel-blockchain/v3/wallet"
 )
 
+// how often every configured node is probed in the background
+const probeInterval = 30 * time.Second
+
+// a node is considered lagging when it is more than this many blocks behind
+// the best known height
+const maxHeightLag = 2
+
+type NodeStatus struct {
+	Url       string
+	Latency   time.Duration
+	Height    uint64
+	TopHash   string
+	Failures  int
+	LastError error
+	LastProbe time.Time
+}
+
+// Healthy reports whether the last probe of the node succeeded
+func (s NodeStatus) Healthy() bool {
+	return !s.LastProbe.IsZero() && s.Failures == 0
+}
+
+// NodeConfig is a configured node. Disabled nodes are kept in the list but are
+// never probed nor used by the wallet.
+type NodeConfig struct {
+	Url     string
+	Enabled bool
+
+	// PinnedKey is an optional "sha256/<base64>" hash of the node's public key.
+	// When set, any other certificate is refused.
+	PinnedKey string
+	// CAFile is an optional PEM bundle trusted instead of the system roots
+	CAFile string
+
+	// Auth holds the optional credentials of the node. They are stored
+	// separately from the node list, see nodeAuthKey.
+	Auth *NodeAuth
+}
+
 type NodeManger struct {
-	rpcUrls []string
+	nodes   []NodeConfig
+	rpcUrls []string // the enabled nodes, in order of preference
+
+	mut    sync.RWMutex
+	status map[string]*NodeStatus
 }
 
 func NewNodeManager(rpcs string) *NodeManger {
-	if len(rpcs) == 0 {
-		rpcs = RPC_URLS
+	nodes := ParseNodes(rpcs)
+	if len(nodes) == 0 {
+		nodes = ParseNodes(RPC_URLS)
+	}
+
+	n := &NodeManger{
+		status: make(map[string]*NodeStatus),
+	}
+	n.SetNodes(nodes)
+	return n
+}
+
+// ParseNodes parses a semicolon-separated node list. Nodes prefixed with "#"
+// are disabled. Each node may be followed by "|pin=<pin>" and "|ca=<path>"
+// attributes.
+func ParseNodes(rpcs string) []NodeConfig {
+	nodes := []NodeConfig{}
+	for _, v := range strings.Split(rpcs, ";") {
+		v = strings.TrimSpace(v)
+		enabled := !strings.HasPrefix(v, "#")
+		v = strings.TrimSpace(strings.TrimPrefix(v, "#"))
+
+		attrs := strings.Split(v, "|")
+		if attrs[0] == "" {
+			continue
+		}
+		node := NodeConfig{
+			Url:     attrs[0],
+			Enabled: enabled,
+		}
+		for _, attr := range attrs[1:] {
+			key, val, _ := strings.Cut(attr, "=")
+			switch key {
+			case "pin":
+				node.PinnedKey = val
+			case "ca":
+				node.CAFile = val
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// FormatNodes is the inverse of ParseNodes
+func FormatNodes(nodes []NodeConfig) string {
+	strs := make([]string, 0, len(nodes))
+	for _, v := range nodes {
+		str := v.Url
+		if !v.Enabled {
+			str = "#" + str
+		}
+		if v.PinnedKey != "" {
+			str += "|pin=" + v.PinnedKey
+		}
+		if v.CAFile != "" {
+			str += "|ca=" + v.CAFile
+		}
+		strs = append(strs, str)
+	}
+	return strings.Join(strs, ";")
+}
+
+// ValidateNodeUrl checks that s is an absolute http or https URL
+func ValidateNodeUrl(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.New("missing host")
+	}
+	return nil
+}
+
+// Start probes all the nodes periodically in the background
+func (n *NodeManger) Start() {
+	go func() {
+		for {
+			n.ProbeAll()
+			time.Sleep(probeInterval)
+		}
+	}()
+}
+
+// ProbeAll queries every configured node concurrently and records its latency,
+// reported height and failure count
+func (n *NodeManger) ProbeAll() {
+	var wg sync.WaitGroup
+	for _, v := range n.Urls() {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+			n.Probe(u)
+		}(v)
+	}
+	wg.Wait()
+}
+
+// Probe queries a single node and returns its updated status
+func (n *NodeManger) Probe(u string) NodeStatus {
+	start := time.Now()
+	info, err := GetNodeInfo(u)
+	latency := time.Since(start)
+
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	st := n.getStatus(u)
+	st.LastProbe = time.Now()
+	if err != nil {
+		st.Failures++
+		st.LastError = err
+		return *st
+	}
+	st.Failures = 0
+	st.LastError = nil
+	st.Latency = latency
+	st.Height = info.Height
+	st.TopHash = info.TopHash
+	return *st
+}
+
+// getStatus returns the status entry of the node, creating it if needed.
+// The caller must hold the lock.
+func (n *NodeManger) getStatus(u string) *NodeStatus {
+	st := n.status[u]
+	if st == nil {
+		st = &NodeStatus{Url: u}
+		n.status[u] = st
+	}
+	return st
+}
+
+func (n *NodeManger) markFailed(u string, err error) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	st := n.getStatus(u)
+	st.Failures++
+	st.LastError = err
+}
+
+// Status returns a copy of the last known status of the node
+func (n *NodeManger) Status(u string) NodeStatus {
+	n.mut.RLock()
+	defer n.mut.RUnlock()
+
+	if st := n.status[u]; st != nil {
+		return *st
+	}
+	return NodeStatus{Url: u}
+}
+
+// Untrusted reports whether the node was last refused because of its
+// certificate
+func (n *NodeManger) Untrusted(u string) bool {
+	return errors.Is(n.Status(u).LastError, ErrUntrustedCert)
+}
+
+// BestHeight returns the highest height reported by any healthy node that
+// agrees with the majority
+func (n *NodeManger) BestHeight() uint64 {
+	n.mut.RLock()
+	defer n.mut.RUnlock()
+
+	var best uint64
+	for _, v := range n.rpcUrls {
+		st := n.status[v]
+		if st != nil && st.Healthy() && st.Height > best && !n.disagrees(st) {
+			best = st.Height
+		}
+	}
+	return best
+}
+
+// Disagrees reports whether the node's chain tip is rejected by the majority of
+// the other healthy nodes, which usually means it is stuck on a fork or lying.
+func (n *NodeManger) Disagrees(u string) bool {
+	n.mut.RLock()
+	defer n.mut.RUnlock()
+
+	st := n.status[u]
+	if st == nil || !st.Healthy() {
+		return false
 	}
+	return n.disagrees(st)
+}
+
+// disagrees compares the tip of st against every other healthy node. Nodes
+// agree when their heights are within maxHeightLag and, if both report a tip
+// hash at the same height, the hashes match. A single other node is not enough
+// to form a majority. The caller must hold the lock.
+func (n *NodeManger) disagrees(st *NodeStatus) bool {
+	agree, disagree := 0, 0
+	for _, v := range n.rpcUrls {
+		o := n.status[v]
+		if o == nil || o == st || !o.Healthy() {
+			continue
+		}
+
+		ok := max(o.Height, st.Height)-min(o.Height, st.Height) <= maxHeightLag
+		if ok && o.Height == st.Height && o.TopHash != "" && st.TopHash != "" {
+			ok = o.TopHash == st.TopHash
+		}
 
-	rpcUrls := strings.Split(rpcs, ";")
-	return &NodeManger{
-		rpcUrls: rpcUrls,
+		if ok {
+			agree++
+		} else {
+			disagree++
+		}
 	}
+	return agree+disagree >= 2 && disagree > agree
 }
 
+// Best returns the fastest healthy node that is not lagging the best known
+// height and agrees with the majority of the other nodes. If no node has been
+// probed successfully yet, the first node that has not failed is returned.
+func (n *NodeManger) Best() string {
+	bestHeight := n.BestHeight()
+
+	n.mut.RLock()
+	defer n.mut.RUnlock()
+
+	best := ""
+	var bestLatency time.Duration
+	for _, v := range n.rpcUrls {
+		st := n.status[v]
+		if st == nil || !st.Healthy() || st.Height+maxHeightLag < bestHeight || n.disagrees(st) {
+			continue
+		}
+		if best == "" || st.Latency < bestLatency {
+			best = v
+			bestLatency = st.Latency
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	for _, v := range n.rpcUrls {
+		if st := n.status[v]; st == nil || st.Failures == 0 {
+			return v
+		}
+	}
+	if len(n.rpcUrls) > 0 {
+		return n.rpcUrls[0]
+	}
+	return ""
+}
+
+// Refresh steers the wallet to the best node and refreshes it. If the refresh
+// fails, all the nodes are probed again and the next best node is tried.
 func (n *NodeManger) Refresh(w *wallet.Wallet) error {
+	if best := n.Best(); best != "" && best != w.GetRpcDaemonAddress() {
+		fmt.Println("switching to node", best)
+		w.SetRpcDaemonAddress(best)
+	}
+
 	err := w.Refresh()
+	if err == nil {
+		return nil
+	}
+
 	origUrl := w.GetRpcDaemonAddress()
-	if err != nil {
-		next := false
-		for _, v := range n.rpcUrls {
-			if v == origUrl {
-				next = true
-				continue
-			}
+	log.Warn("node", origUrl, "refresh failed:", err)
+	n.markFailed(origUrl, err)
+	n.ProbeAll()
 
-			if next {
-				fmt.Println("trying with node", v)
-				w.SetRpcDaemonAddress(v)
-				err = w.Refresh()
-				if err == nil {
-					return nil
-				} else {
-					log.Warn(err)
-				}
-				break
-			}
-		}
-		if !next && len(n.rpcUrls) > 1 {
-			fmt.Println("trying with first node", n.rpcUrls[0])
-			w.SetRpcDaemonAddress(n.rpcUrls[0])
-			err = w.Refresh()
-			if err == nil {
-				return nil
-			} else {
-				log.Warn(err)
-			}
+	if best := n.Best(); best != "" && best != origUrl {
+		fmt.Println("trying with node", best)
+		w.SetRpcDaemonAddress(best)
+		err = w.Refresh()
+		if err != nil {
+			log.Warn(err)
+			n.markFailed(best, err)
 		}
 	}
 	return err
 }
 
 func (n *NodeManger) Urls() []string {
+	n.mut.RLock()
+	defer n.mut.RUnlock()
+
 	return n.rpcUrls
 }
+
+// Nodes returns a copy of all the configured nodes, including disabled ones
+func (n *NodeManger) Nodes() []NodeConfig {
+	n.mut.RLock()
+	defer n.mut.RUnlock()
+
+	return slices.Clone(n.nodes)
+}
+
+func (n *NodeManger) SetNodes(nodes []NodeConfig) {
+	n.mut.Lock()
+	defer n.mut.Unlock()
+
+	n.nodes = slices.Clone(nodes)
+	n.rpcUrls = make([]string, 0, len(nodes))
+	for _, v := range nodes {
+		if v.Enabled {
+			n.rpcUrls = append(n.rpcUrls, v.Url)
+		}
+	}
+	applyNodeTls(nodes)
+	applyNodeAuth(nodes)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeNode is a daemon answering get_info with a fixed chain tip
+type fakeNode struct {
+	height  uint64
+	topHash string
+	delay   time.Duration
+	fail    bool
+}
+
+func (f fakeNode) start(t *testing.T) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.fail {
+			http.Error(w, "node is down", http.StatusInternalServerError)
+			return
+		}
+		time.Sleep(f.delay)
+
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "get_info" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"result": NodeInfo{Height: f.height, TopHash: f.topHash},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func newTestNodeManager(urls ...string) *NodeManger {
+	nodes := make([]NodeConfig, len(urls))
+	for i, v := range urls {
+		nodes[i] = NodeConfig{Url: v, Enabled: true}
+	}
+	n := &NodeManger{
+		status: make(map[string]*NodeStatus),
+	}
+	n.SetNodes(nodes)
+	return n
+}
+
+func TestBest(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []fakeNode
+		want  int // index of the expected node
+	}{
+		{
+			name: "fastest node",
+			nodes: []fakeNode{
+				{height: 100, topHash: "a", delay: 200 * time.Millisecond},
+				{height: 100, topHash: "a"},
+				{height: 100, topHash: "a", delay: 200 * time.Millisecond},
+			},
+			want: 1,
+		},
+		{
+			name: "lagging node is skipped",
+			nodes: []fakeNode{
+				{height: 100, topHash: "a", delay: 200 * time.Millisecond},
+				{height: 100 - maxHeightLag - 1, topHash: "b"},
+				{height: 100, topHash: "a", delay: 200 * time.Millisecond},
+			},
+			want: 0,
+		},
+		{
+			name: "lag within the limit is tolerated",
+			nodes: []fakeNode{
+				{height: 100, topHash: "a", delay: 200 * time.Millisecond},
+				{height: 100 - maxHeightLag, topHash: "b"},
+			},
+			want: 1,
+		},
+		{
+			name: "failing node is skipped",
+			nodes: []fakeNode{
+				{fail: true},
+				{height: 100, topHash: "a", delay: 200 * time.Millisecond},
+			},
+			want: 1,
+		},
+		{
+			name: "node on a fork is skipped",
+			nodes: []fakeNode{
+				{height: 100, topHash: "fork"},
+				{height: 100, topHash: "a", delay: 200 * time.Millisecond},
+				{height: 100, topHash: "a", delay: 200 * time.Millisecond},
+			},
+			want: 1,
+		},
+		{
+			name: "every node failing",
+			nodes: []fakeNode{
+				{fail: true},
+				{fail: true},
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := make([]string, len(tt.nodes))
+			for i, v := range tt.nodes {
+				urls[i] = v.start(t)
+			}
+			n := newTestNodeManager(urls...)
+			n.ProbeAll()
+
+			if got := n.Best(); got != urls[tt.want] {
+				t.Errorf("Best() = %s, want node %d (%s)", got, tt.want, urls[tt.want])
+			}
+		})
+	}
+}
+
+func TestBestBeforeProbe(t *testing.T) {
+	n := newTestNodeManager("http://a.invalid", "http://b.invalid")
+	if got := n.Best(); got != "http://a.invalid" {
+		t.Errorf("Best() = %s, want the first node", got)
+	}
+
+	n.markFailed("http://a.invalid", http.ErrHandlerTimeout)
+	if got := n.Best(); got != "http://b.invalid" {
+		t.Errorf("Best() = %s, want the first node that has not failed", got)
+	}
+}
+
+func TestProbeRecordsStatus(t *testing.T) {
+	ok := fakeNode{height: 42, topHash: "abc"}.start(t)
+	down := fakeNode{fail: true}.start(t)
+	n := newTestNodeManager(ok, down)
+	n.ProbeAll()
+
+	st := n.Status(ok)
+	if !st.Healthy() || st.Height != 42 || st.TopHash != "abc" {
+		t.Errorf("status of the healthy node = %+v", st)
+	}
+	st = n.Status(down)
+	if st.Healthy() || st.Failures != 1 || st.LastError == nil {
+		t.Errorf("status of the failing node = %+v", st)
+	}
+
+	n.ProbeAll()
+	if st := n.Status(down); st.Failures != 2 {
+		t.Errorf("failures = %d, want 2", st.Failures)
+	}
+}
+
+func TestDisagrees(t *testing.T) {
+	type tip struct {
+		height  uint64
+		topHash string
+	}
+	tests := []struct {
+		name string
+		tips []tip
+		want []bool
+	}{
+		{
+			name: "same tip",
+			tips: []tip{{100, "a"}, {100, "a"}, {100, "a"}},
+			want: []bool{false, false, false},
+		},
+		{
+			name: "minority fork",
+			tips: []tip{{100, "a"}, {100, "a"}, {100, "b"}},
+			want: []bool{false, false, true},
+		},
+		{
+			name: "no majority with two nodes",
+			tips: []tip{{100, "a"}, {100, "b"}},
+			want: []bool{false, false},
+		},
+		{
+			name: "stuck node",
+			tips: []tip{{100, "a"}, {101, "b"}, {90, "c"}},
+			want: []bool{false, false, true},
+		},
+		{
+			name: "different heights within the lag are not compared by hash",
+			tips: []tip{{100, "a"}, {101, "b"}, {102, "c"}},
+			want: []bool{false, false, false},
+		},
+		{
+			name: "missing hash",
+			tips: []tip{{100, ""}, {100, "a"}, {100, "b"}},
+			want: []bool{false, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			urls := make([]string, len(tt.tips))
+			for i := range tt.tips {
+				urls[i] = "http://node" + string(rune('a'+i)) + ".invalid"
+			}
+			n := newTestNodeManager(urls...)
+			for i, v := range tt.tips {
+				n.status[urls[i]] = &NodeStatus{
+					Url:       urls[i],
+					Height:    v.height,
+					TopHash:   v.topHash,
+					LastProbe: time.Now(),
+				}
+			}
+
+			for i, u := range urls {
+				if got := n.Disagrees(u); got != tt.want[i] {
+					t.Errorf("Disagrees(node %d) = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDisagreesIgnoresUnhealthyNodes(t *testing.T) {
+	n := newTestNodeManager("http://a.invalid", "http://b.invalid", "http://c.invalid")
+	n.status["http://a.invalid"] = &NodeStatus{Height: 100, TopHash: "a", LastProbe: time.Now()}
+	n.status["http://b.invalid"] = &NodeStatus{Height: 100, TopHash: "b", LastProbe: time.Now()}
+	n.status["http://c.invalid"] = &NodeStatus{Height: 100, TopHash: "b", LastProbe: time.Now(), Failures: 1}
+
+	if n.Disagrees("http://a.invalid") {
+		t.Error("a failing node must not count towards the majority")
+	}
+	if n.Disagrees("http://c.invalid") {
+		t.Error("a failing node is never reported as disagreeing")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const nodeRpcTimeout = 8 * time.Second
+
+// NodeInfo is the subset of the daemon's get_info response used by the GUI
+type NodeInfo struct {
+	Height  uint64 `json:"height"`
+	TopHash string `json:"top_hash"`
+}
+
+// blockResponse is the subset of the daemon's get_block_by_height response
+// used by the GUI
+type blockResponse struct {
+	Block struct {
+		// Timestamp is in milliseconds since the Unix epoch
+		Timestamp uint64 `json:"timestamp"`
+	} `json:"block"`
+}
+
+type rpcRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	Id      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// callNode performs a JSON-RPC call against the daemon at rpcUrl
+func callNode(rpcUrl, method string, params any, result any) error {
+	body, err := json.Marshal(rpcRequest{
+		JsonRpc: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: nodeRpcTimeout}
+	res, err := client.Post(strings.TrimSuffix(rpcUrl, "/")+"/json_rpc", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("node returned status %s", res.Status)
+	}
+
+	var resp rpcResponse
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("rpc error %d: %s", resp.Error.Code, resp.Error.Message)
+	}
+	if resp.Result == nil {
+		return errors.New("empty rpc result")
+	}
+
+	return json.Unmarshal(resp.Result, result)
+}
+
+func GetNodeInfo(rpcUrl string) (*NodeInfo, error) {
+	info := &NodeInfo{}
+	err := callNode(rpcUrl, "get_info", nil, info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetBlockTime returns the timestamp of the block at the given height
+func GetBlockTime(rpcUrl string, height uint64) (time.Time, error) {
+	res := &blockResponse{}
+	err := callNode(rpcUrl, "get_block_by_height", map[string]any{"height": height}, res)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(int64(res.Block.Timestamp)), nil
+}
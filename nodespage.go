@@ -0,0 +1,285 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+// pageNodes shows the node manager. Changes are only applied when saved;
+// onClose is called when leaving the page.
+func pageNodes(onClose func()) {
+	title := NewTitle(T.NodeManager)
+
+	nodes := nodeManager.Nodes()
+
+	rows := container.NewVBox()
+
+	var rebuild func()
+
+	testNode := func(u string) {
+		go func() {
+			nodeManager.Probe(u)
+			fyne.Do(rebuild)
+		}()
+	}
+
+	rebuild = func() {
+		rows.RemoveAll()
+
+		for i, v := range nodes {
+			urlLbl := widget.NewLabel(v.Url)
+			urlLbl.TextStyle.Bold = true
+			urlLbl.Truncation = fyne.TextTruncateEllipsis
+
+			statusLbl := widget.NewLabel(nodeStatusText(nodeManager.Status(v.Url)))
+			statusLbl.Wrapping = fyne.TextWrapWord
+
+			enabled := widget.NewCheck(T.NodeEnabled, func(b bool) {
+				nodes[i].Enabled = b
+			})
+			enabled.SetChecked(v.Enabled)
+
+			upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+				nodes[i-1], nodes[i] = nodes[i], nodes[i-1]
+				rebuild()
+			})
+			if i == 0 {
+				upBtn.Disable()
+			}
+			downBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+				nodes[i+1], nodes[i] = nodes[i], nodes[i+1]
+				rebuild()
+			})
+			if i == len(nodes)-1 {
+				downBtn.Disable()
+			}
+			testBtn := widget.NewButtonWithIcon(T.TestNode, theme.ViewRefreshIcon(), func() {
+				testNode(v.Url)
+			})
+			removeBtn := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				nodes = slices.Delete(nodes, i, i+1)
+				rebuild()
+			})
+
+			buttons := container.NewHBox(enabled, testBtn, upBtn, downBtn, removeBtn)
+			buttons.Add(widget.NewButtonWithIcon("", theme.AccountIcon(), func() {
+				nodeAuthDialog(&nodes[i], rebuild)
+			}))
+			// TLS is handled by the browser on the web build
+			if runtime.GOOS != "js" {
+				buttons.Add(widget.NewButtonWithIcon("", theme.SettingsIcon(), func() {
+					nodeTlsDialog(&nodes[i], rebuild)
+				}))
+			}
+
+			rows.Add(container.NewVBox(
+				container.NewBorder(nil, nil, nil, buttons, urlLbl),
+				statusLbl,
+				widget.NewSeparator(),
+			))
+		}
+		rows.Refresh()
+	}
+	rebuild()
+
+	newUrl := widget.NewEntry()
+	newUrl.SetPlaceHolder("https://node.example.org:443")
+	newUrl.Validator = func(s string) error {
+		if s == "" {
+			return nil
+		}
+		if err := ValidateNodeUrl(s); err != nil {
+			return fmt.Errorf(T.ErrInvalidNodeUrl, err)
+		}
+		return nil
+	}
+
+	addBtn := widget.NewButtonWithIcon(T.AddNode, theme.ContentAddIcon(), func() {
+		u := newUrl.Text
+		if err := ValidateNodeUrl(u); err != nil {
+			ErrorDialog(w, fmt.Errorf(T.ErrInvalidNodeUrl, err))
+			return
+		}
+		if slices.ContainsFunc(nodes, func(n NodeConfig) bool { return n.Url == u }) {
+			ErrorDialog(w, errors.New(T.ErrDuplicateNode))
+			return
+		}
+		nodes = append(nodes, NodeConfig{
+			Url:     u,
+			Enabled: true,
+		})
+		newUrl.SetText("")
+		rebuild()
+		testNode(u)
+	})
+
+	testAllBtn := widget.NewButtonWithIcon(T.TestAllNodes, theme.ViewRefreshIcon(), func() {
+		for _, v := range nodes {
+			testNode(v.Url)
+		}
+	})
+
+	backBtn := widget.NewButtonWithIcon(T.Back, theme.NavigateBackIcon(), onClose)
+
+	saveBtn := widget.NewButtonWithIcon(T.Save, theme.DocumentSaveIcon(), func() {
+		err := saveNodes(nodes)
+		if err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		onClose()
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	top := container.NewVBox(
+		title,
+		container.NewBorder(nil, nil, nil, addBtn, newUrl),
+	)
+	bottom := container.NewHBox(backBtn, layout.NewSpacer(), testAllBtn, saveBtn)
+
+	w.SetContent(container.NewPadded(container.NewBorder(top, bottom, nil, nil, container.NewVScroll(rows))))
+}
+
+// saveNodes validates the node list, applies it to the node manager and
+// persists it
+func saveNodes(nodes []NodeConfig) error {
+	numEnabled := 0
+	for _, v := range nodes {
+		if err := ValidateNodeUrl(v.Url); err != nil {
+			return fmt.Errorf(T.ErrInvalidNodeUrl, err)
+		}
+		if v.PinnedKey != "" {
+			if _, err := ParsePin(v.PinnedKey); err != nil {
+				return fmt.Errorf(T.ErrInvalidNodePin, v.Url, err)
+			}
+		}
+		if v.Enabled {
+			numEnabled++
+		}
+	}
+	if numEnabled == 0 {
+		return errors.New(T.ErrNoNodeEnabled)
+	}
+
+	err := save.UpdateSettings(func(s *save.Settings) {
+		s.Nodes = FormatNodes(nodes)
+	})
+	if err != nil {
+		return err
+	}
+	err = SaveNodeAuth(nodes)
+	if err != nil {
+		return err
+	}
+
+	nodeManager.SetNodes(nodes)
+	go nodeManager.ProbeAll()
+
+	return nil
+}
+
+// nodeTlsDialog edits the certificate pin and CA bundle of the node
+func nodeTlsDialog(node *NodeConfig, onSave func()) {
+	pin := widget.NewEntry()
+	pin.SetPlaceHolder(pinPrefix + "...")
+	pin.SetText(node.PinnedKey)
+	pin.Validator = func(s string) error {
+		if s == "" {
+			return nil
+		}
+		_, err := ParsePin(s)
+		return err
+	}
+
+	caFile := widget.NewEntry()
+	caFile.SetText(node.CAFile)
+
+	formItems := []*widget.FormItem{
+		{Text: T.PinnedKey, Widget: pin, HintText: T.PinnedKeyHint},
+		{Text: T.CAFile, Widget: caFile, HintText: T.CAFileHint},
+	}
+
+	dialog.NewForm(T.NodeTls, T.Ok, T.Cancel, formItems, func(b bool) {
+		if !b {
+			return
+		}
+		node.PinnedKey = strings.TrimSpace(pin.Text)
+		node.CAFile = strings.TrimSpace(caFile.Text)
+		onSave()
+	}, w).Show()
+}
+
+// nodeAuthDialog edits the credentials sent to the node
+func nodeAuthDialog(node *NodeConfig, onSave func()) {
+	auth := node.Auth
+	if auth == nil {
+		auth = &NodeAuth{}
+	}
+
+	username := widget.NewEntry()
+	username.SetText(auth.Username)
+	password := widget.NewPasswordEntry()
+	password.SetText(auth.Password)
+	token := widget.NewPasswordEntry()
+	token.SetText(auth.Token)
+
+	headers := widget.NewMultiLineEntry()
+	headers.SetPlaceHolder("X-Api-Key: ...")
+	headers.SetText(FormatHeaders(auth.Headers))
+	headers.Validator = func(s string) error {
+		_, err := ParseHeaders(s)
+		return err
+	}
+
+	formItems := []*widget.FormItem{
+		{Text: T.Username, Widget: username},
+		{Text: T.Password, Widget: password},
+		{Text: T.BearerToken, Widget: token, HintText: T.BearerTokenHint},
+		{Text: T.CustomHeaders, Widget: headers, HintText: T.CustomHeadersHint},
+	}
+
+	dialog.NewForm(T.NodeCredentials, T.Ok, T.Cancel, formItems, func(b bool) {
+		if !b {
+			return
+		}
+		h, err := ParseHeaders(headers.Text)
+		if err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		node.Auth = &NodeAuth{
+			Username: strings.TrimSpace(username.Text),
+			Password: password.Text,
+			Token:    strings.TrimSpace(token.Text),
+			Headers:  h,
+		}
+		onSave()
+	}, w).Show()
+}
+
+func nodeStatusText(st NodeStatus) string {
+	if st.LastProbe.IsZero() {
+		return T.NodeNotTested
+	}
+	if errors.Is(st.LastError, ErrUntrustedCert) {
+		return T.NodeUntrusted + ": " + st.LastError.Error()
+	}
+	if st.LastError != nil {
+		return T.LastError + ": " + st.LastError.Error()
+	}
+	return T.Latency + ": " + st.Latency.Round(time.Millisecond).String() + "   " +
+		T.Height + ": " + strconv.FormatUint(st.Height, 10)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+const pinPrefix = "sha256/"
+
+var ErrUntrustedCert = errors.New("untrusted certificate")
+
+type UntrustedCertError struct {
+	Host string
+	Err  error
+}
+
+func (e *UntrustedCertError) Error() string {
+	return fmt.Sprintf("untrusted certificate for %s: %v", e.Host, e.Err)
+}
+func (e *UntrustedCertError) Unwrap() []error {
+	return []error{ErrUntrustedCert, e.Err}
+}
+
+// tlsTransport sends the requests to the nodes with custom trust settings
+// through a transport dedicated to their host. The host is taken from the
+// request URL, as the SNI server name is empty for IP addresses.
+type tlsTransport struct{}
+
+var tlsMut sync.RWMutex
+var tlsByHost = map[string]*http.Transport{}
+
+func (tlsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tr := baseTransport
+	if req.URL.Scheme == "https" {
+		tlsMut.RLock()
+		if v := tlsByHost[strings.ToLower(req.URL.Hostname())]; v != nil {
+			tr = v
+		}
+		tlsMut.RUnlock()
+	}
+
+	res, err := tr.RoundTrip(req)
+	var verr *tls.CertificateVerificationError
+	if err != nil && errors.As(err, &verr) && !errors.Is(err, ErrUntrustedCert) {
+		err = &UntrustedCertError{req.URL.Hostname(), err}
+	}
+	return res, err
+}
+
+// closeIdleConnections makes sure no connection opened before a change of the
+// network settings is reused
+func closeIdleConnections() {
+	baseTransport.CloseIdleConnections()
+
+	tlsMut.RLock()
+	defer tlsMut.RUnlock()
+	for _, v := range tlsByHost {
+		v.CloseIdleConnections()
+	}
+}
+
+// ParsePin decodes a "sha256/<base64>" SPKI pin
+func ParsePin(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, pinPrefix) {
+		return nil, fmt.Errorf("pin must start with %q", pinPrefix)
+	}
+	pin, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, pinPrefix))
+	if err != nil {
+		return nil, err
+	}
+	if len(pin) != sha256.Size {
+		return nil, errors.New("invalid pin length")
+	}
+	return pin, nil
+}
+
+// applyNodeTls replaces the trust settings with the ones of the given nodes
+func applyNodeTls(nodes []NodeConfig) {
+	byHost := map[string]*http.Transport{}
+
+	for _, v := range nodes {
+		if v.PinnedKey == "" && v.CAFile == "" {
+			continue
+		}
+		u, err := url.Parse(v.Url)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+
+		tr := baseTransport.Clone()
+		tr.TLSClientConfig = nodeTlsConfig(v, host)
+		byHost[host] = tr
+	}
+
+	tlsMut.Lock()
+	old := tlsByHost
+	tlsByHost = byHost
+	tlsMut.Unlock()
+
+	baseTransport.CloseIdleConnections()
+	for _, v := range old {
+		v.CloseIdleConnections()
+	}
+}
+
+// nodeTlsConfig returns the TLS configuration of a node with a pinned key or
+// a CA bundle. Invalid settings make every connection to the node fail, rather
+// than falling back to the default verification.
+func nodeTlsConfig(node NodeConfig, host string) *tls.Config {
+	if node.PinnedKey != "" {
+		pin, err := ParsePin(node.PinnedKey)
+		if err != nil {
+			log.Warn("invalid pin for node", node.Url, err)
+			return refuseTls(host, fmt.Errorf("invalid pinned key: %w", err))
+		}
+		// the pin replaces the verification of the certificate chain, so
+		// that self-signed certificates can be used
+		return &tls.Config{
+			InsecureSkipVerify: true,
+			VerifyConnection: func(cs tls.ConnectionState) error {
+				if len(cs.PeerCertificates) == 0 {
+					return &UntrustedCertError{host, errors.New("no certificate")}
+				}
+				sum := sha256.Sum256(cs.PeerCertificates[0].RawSubjectPublicKeyInfo)
+				if !bytes.Equal(sum[:], pin) {
+					return &UntrustedCertError{host, errors.New("certificate does not match the pinned key")}
+				}
+				return nil
+			},
+		}
+	}
+
+	pem, err := os.ReadFile(node.CAFile)
+	if err != nil {
+		log.Warn("failed to read CA bundle", node.CAFile, err)
+		return refuseTls(host, fmt.Errorf("failed to read CA bundle: %w", err))
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(pem) {
+		log.Warn("no certificate found in CA bundle", node.CAFile)
+		return refuseTls(host, errors.New("no certificate found in CA bundle"))
+	}
+	// the default verification checks the chain and the host name, which the
+	// transport sets to the dialed host, IP addresses included
+	return &tls.Config{
+		RootCAs: roots,
+	}
+}
+
+// refuseTls returns a TLS configuration that fails every handshake with err
+func refuseTls(host string, err error) *tls.Config {
+	return &tls.Config{
+		InsecureSkipVerify: true,
+		VerifyConnection: func(tls.ConnectionState) error {
+			return &UntrustedCertError{host, err}
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	stdlog "log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTlsNode starts a node with its own self-signed certificate, valid for
+// 127.0.0.1 only
+func newTlsNode(t *testing.T) *httptest.Server {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"result": NodeInfo{Height: 1},
+		})
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+	srv.Config.ErrorLog = stdlog.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func pinOf(srv *httptest.Server) string {
+	sum := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	return pinPrefix + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func caFileOf(t *testing.T, srv *httptest.Server) string {
+	p := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(p, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestNodeTls(t *testing.T) {
+	// the test servers listen on 127.0.0.1, for which no SNI is sent
+	srv := newTlsNode(t)
+	other := newTlsNode(t)
+
+	emptyFile := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(emptyFile, nil, 0o600)
+
+	tests := []struct {
+		name    string
+		url     string
+		pin     string
+		caFile  string
+		trusted bool
+	}{
+		{name: "self-signed without settings"},
+		{name: "matching pin", pin: pinOf(srv), trusted: true},
+		{name: "pin of another node", pin: pinOf(other)},
+		{name: "invalid pin", pin: pinPrefix + "AAAA"},
+		{name: "CA bundle", caFile: caFileOf(t, srv), trusted: true},
+		{name: "CA bundle of another node", caFile: caFileOf(t, other)},
+		{name: "missing CA bundle", caFile: filepath.Join(t.TempDir(), "missing.pem")},
+		{name: "CA bundle without certificate", caFile: emptyFile},
+		{
+			// the certificate is valid for 127.0.0.1 but not for localhost
+			name:   "host name is verified",
+			url:    strings.Replace(srv.URL, "127.0.0.1", "localhost", 1),
+			caFile: caFileOf(t, srv),
+		},
+	}
+
+	defer applyNodeTls(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := srv.URL
+			if tt.url != "" {
+				u = tt.url
+			}
+			applyNodeTls([]NodeConfig{
+				{Url: u, Enabled: true, PinnedKey: tt.pin, CAFile: tt.caFile},
+			})
+
+			_, err := GetNodeInfo(u)
+			if tt.trusted && err != nil {
+				t.Errorf("node refused: %v", err)
+			}
+			if !tt.trusted && !errors.Is(err, ErrUntrustedCert) {
+				t.Errorf("error = %v, want ErrUntrustedCert", err)
+			}
+		})
+	}
+}
+
+func TestNodeTlsIsPerHost(t *testing.T) {
+	srv := newTlsNode(t)
+	other := newTlsNode(t)
+
+	// both servers are on 127.0.0.1, so the pin applies to both of them
+	defer applyNodeTls(nil)
+	applyNodeTls([]NodeConfig{
+		{Url: srv.URL, Enabled: true, PinnedKey: pinOf(srv)},
+	})
+
+	if _, err := GetNodeInfo(srv.URL); err != nil {
+		t.Errorf("pinned node refused: %v", err)
+	}
+	if _, err := GetNodeInfo(other.URL); !errors.Is(err, ErrUntrustedCert) {
+		t.Errorf("error = %v, another certificate on the pinned host must be refused", err)
+	}
+
+	applyNodeTls(nil)
+	if _, err := GetNodeInfo(srv.URL); !errors.Is(err, ErrUntrustedCert) {
+		t.Errorf("error = %v, the pin must be removed with the node", err)
+	}
+}
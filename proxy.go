@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+var ErrInvalidProxy = errors.New("invalid proxy")
+
+var proxyMut sync.RWMutex
+var proxyUrl *url.URL
+
+// proxyErr is set when the proxy setting is invalid. All the traffic is then
+// refused rather than sent directly, which would reveal the user's IP address.
+var proxyErr error
+var invalidProxy string
+
+func init() {
+	baseTransport.Proxy = proxyFunc
+}
+
+func proxyFunc(req *http.Request) (*url.URL, error) {
+	proxyMut.RLock()
+	defer proxyMut.RUnlock()
+
+	if proxyErr != nil {
+		return nil, proxyErr
+	}
+	if proxyUrl == nil {
+		return http.ProxyFromEnvironment(req)
+	}
+	return proxyUrl, nil
+}
+
+// ParseProxyUrl parses a socks5://, socks5h://, http:// or https:// proxy URL
+func ParseProxyUrl(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h", "http", "https":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing proxy host")
+	}
+	return u, nil
+}
+
+// SetProxy routes all the HTTP traffic through the given proxy. An empty
+// string restores direct connections. If s is invalid, all the traffic is
+// refused until a valid proxy is set.
+func SetProxy(s string) error {
+	var u *url.URL
+	var err error
+	if s != "" {
+		u, err = ParseProxyUrl(s)
+		if err != nil {
+			err = fmt.Errorf("%w: %v", ErrInvalidProxy, err)
+		}
+	}
+
+	proxyMut.Lock()
+	proxyUrl = u
+	proxyErr = err
+	invalidProxy = ""
+	if err != nil {
+		invalidProxy = s
+	}
+	proxyMut.Unlock()
+
+	closeIdleConnections()
+	return err
+}
+
+// ProxyError returns the error of the proxy setting, if it is invalid
+func ProxyError() error {
+	proxyMut.RLock()
+	defer proxyMut.RUnlock()
+
+	return proxyErr
+}
+
+// GetProxy returns the proxy setting, even if it is invalid so that it can be
+// corrected
+func GetProxy() string {
+	proxyMut.RLock()
+	defer proxyMut.RUnlock()
+
+	if proxyUrl == nil {
+		return invalidProxy
+	}
+	return proxyUrl.String()
+}
+
+func proxyDialog() {
+	proxyInput := widget.NewEntry()
+	proxyInput.SetPlaceHolder("socks5://127.0.0.1:9050")
+	proxyInput.SetText(GetProxy())
+	proxyInput.Validator = func(s string) error {
+		if s == "" {
+			return nil
+		}
+		_, err := ParseProxyUrl(s)
+		return err
+	}
+
+	formItems := []*widget.FormItem{
+		{Text: T.ProxyUrl, Widget: proxyInput, HintText: T.ProxyHint},
+	}
+
+	dialog.NewForm(T.ProxySettings, T.Save, T.Cancel, formItems, func(b bool) {
+		if !b {
+			return
+		}
+
+		proxy := strings.TrimSpace(proxyInput.Text)
+		err := SetProxy(proxy)
+		if err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		err = save.UpdateSettings(func(s *save.Settings) {
+			s.Proxy = proxy
+		})
+		if err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		go nodeManager.ProbeAll()
+	}, w).Show()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeProxy is an HTTP proxy stand-in that records the requested hosts. It
+// answers node RPC calls itself and refuses CONNECT tunnels.
+type fakeProxy struct {
+	mut   sync.Mutex
+	hosts []string
+}
+
+func (p *fakeProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mut.Lock()
+	p.hosts = append(p.hosts, r.Host)
+	p.mut.Unlock()
+
+	if r.Method == http.MethodConnect {
+		http.Error(w, "tunnels are not supported", http.StatusForbidden)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"result": NodeInfo{Height: 7},
+	})
+}
+
+func (p *fakeProxy) seen(host string) bool {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	return slices.Contains(p.hosts, host)
+}
+
+func TestProxyRoutesAllTraffic(t *testing.T) {
+	proxy := &fakeProxy{}
+	srv := httptest.NewServer(proxy)
+	defer srv.Close()
+
+	if err := SetProxy(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	defer SetProxy("")
+
+	// node RPC
+	info, err := GetNodeInfo("http://node.invalid:6311")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Height != 7 {
+		t.Errorf("height = %d, the request did not reach the proxy", info.Height)
+	}
+	if !proxy.seen("node.invalid:6311") {
+		t.Error("node RPC did not go through the proxy")
+	}
+
+	// the update checker, like the wallet, uses the default client
+	u, err := url.Parse(updateUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Get(updateUrl)
+	if err == nil {
+		res.Body.Close()
+	}
+	if !proxy.seen(u.Host + ":443") {
+		t.Error("update check did not go through the proxy")
+	}
+}
+
+func TestInvalidProxyFailsClosed(t *testing.T) {
+	var hits atomic.Int32
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"result": NodeInfo{Height: 1},
+		})
+	}))
+	defer node.Close()
+
+	err := SetProxy("ftp://127.0.0.1:21")
+	if !errors.Is(err, ErrInvalidProxy) {
+		t.Fatalf("SetProxy error = %v, want ErrInvalidProxy", err)
+	}
+	defer SetProxy("")
+
+	if GetProxy() != "ftp://127.0.0.1:21" {
+		t.Errorf("GetProxy() = %q, the invalid setting must be kept to be corrected", GetProxy())
+	}
+	_, err = GetNodeInfo(node.URL)
+	if !errors.Is(err, ErrInvalidProxy) {
+		t.Errorf("GetNodeInfo error = %v, want ErrInvalidProxy", err)
+	}
+	if hits.Load() != 0 {
+		t.Error("the node was reached directly with an invalid proxy")
+	}
+
+	if err := SetProxy(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetNodeInfo(node.URL); err != nil {
+		t.Errorf("direct connection after clearing the proxy: %v", err)
+	}
+}
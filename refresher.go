@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// interval between two refreshes while the node is reachable
+const refreshInterval = 10 * time.Second
+
+// bounds of the exponential backoff used after a failed refresh
+const minRetryDelay = 5 * time.Second
+const maxRetryDelay = 5 * time.Minute
+
+// the wallet is considered offline after this many consecutive failures
+const offlineAfterFailures = 3
+
+// RefreshScheduler decides when the wallet is refreshed next. Failures are
+// retried with an exponential backoff and jitter, so that a node outage does
+// not turn into a busy loop. It must only be used by the refresh goroutine,
+// except for RetryNow.
+type RefreshScheduler struct {
+	failures int
+	retry    chan struct{}
+}
+
+func NewRefreshScheduler() *RefreshScheduler {
+	return &RefreshScheduler{
+		retry: make(chan struct{}, 1),
+	}
+}
+
+// Done records the result of a refresh
+func (s *RefreshScheduler) Done(err error) {
+	if err != nil {
+		s.failures++
+	} else {
+		s.failures = 0
+	}
+}
+
+func (s *RefreshScheduler) Failures() int {
+	return s.failures
+}
+
+// Offline reports whether enough refreshes failed in a row to consider the
+// wallet disconnected
+func (s *RefreshScheduler) Offline() bool {
+	return s.failures >= offlineAfterFailures
+}
+
+// Delay returns how long to wait before the next refresh
+func (s *RefreshScheduler) Delay() time.Duration {
+	if s.failures == 0 {
+		return refreshInterval
+	}
+
+	d := maxRetryDelay
+	if s.failures < 16 {
+		d = min(minRetryDelay<<(s.failures-1), maxRetryDelay)
+	}
+
+	// "equal jitter": wait between half and the full backoff
+	return d/2 + rand.N(d/2+1)
+}
+
+// Wait blocks for d, or until RetryNow is called. It returns false if ctx is
+// cancelled first.
+func (s *RefreshScheduler) Wait(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-s.retry:
+	case <-ctx.Done():
+		return false
+	}
+	return true
+}
+
+// RetryNow wakes up the refresh goroutine if it is waiting
+func (s *RefreshScheduler) RetryNow() {
+	select {
+	case s.retry <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,80 @@
+package save
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// number of previous versions kept for each wallet
+const maxBackups = 10
+
+const backupTimeFormat = "20060102-150405.000000000"
+
+func backupPrefix(name string) string {
+	return "backups/" + name + "/"
+}
+
+// backupWallet copies the current version of the wallet, if any, to its backups
+// and removes the oldest ones
+func backupWallet(name string) error {
+	data, err := wallets.Get(name + ".keys")
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = wallets.Set(backupPrefix(name)+time.Now().UTC().Format(backupTimeFormat)+".keys", data)
+	if err != nil {
+		return err
+	}
+
+	backups, err := ListBackups(name)
+	if err != nil {
+		return err
+	}
+	for _, v := range backups[min(len(backups), maxBackups):] {
+		wallets.Delete(backupPrefix(name) + v + ".keys")
+	}
+	return nil
+}
+
+// ListBackups returns the backups of the wallet, newest first
+func ListBackups(name string) ([]string, error) {
+	keys, err := wallets.Keys(backupPrefix(name))
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []string{}
+	for _, v := range keys {
+		v = strings.TrimPrefix(v, backupPrefix(name))
+		if !strings.Contains(v, "/") && strings.HasSuffix(v, ".keys") {
+			backups = append(backups, strings.TrimSuffix(v, ".keys"))
+		}
+	}
+	slices.Sort(backups)
+	slices.Reverse(backups)
+	return backups, nil
+}
+
+func ReadBackup(name, backup string) ([]byte, error) {
+	return wallets.Get(backupPrefix(name) + backup + ".keys")
+}
+
+// BackupTime returns when the backup was made
+func BackupTime(backup string) (time.Time, error) {
+	return time.Parse(backupTimeFormat, backup)
+}
+
+// RestoreBackup replaces the wallet with the given backup. The replaced version
+// is itself backed up.
+func RestoreBackup(name, backup string) error {
+	data, err := ReadBackup(name, backup)
+	if err != nil {
+		return err
+	}
+	return SaveWallet(name, data)
+}
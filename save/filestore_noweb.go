@@ -0,0 +1,151 @@
+//go:build !js
+// +build !js
+
+package save
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+func init() {
+	wallets = &FileStore{Dir: "."}
+	settings = &FileStore{Dir: "."}
+	// the settings must be found regardless of the working directory
+	if dir, err := configDir(); err == nil {
+		settings = &FileStore{Dir: dir}
+	}
+}
+
+// FileStore is a Store backed by the files of a directory. Writes are atomic.
+type FileStore struct {
+	Dir string
+}
+
+func (f *FileStore) path(key string) (string, error) {
+	if !fs.ValidPath(key) {
+		return "", &fs.PathError{Op: "open", Path: key, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(f.Dir, filepath.FromSlash(key)), nil
+}
+
+func (f *FileStore) Get(key string) ([]byte, error) {
+	p, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+func (f *FileStore) Set(key string, data []byte) error {
+	p, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(p), 0o700)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p, data, 0o600)
+}
+
+func (f *FileStore) Delete(key string) error {
+	p, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Keys returns the keys starting with prefix. Only the directory of prefix
+// and its subdirectories are walked, so Keys("") lists the top level only: the
+// wallet directory may be a large folder chosen by the user, such as the home
+// directory. Hidden and unreadable subdirectories are skipped.
+func (f *FileStore) Keys(prefix string) ([]string, error) {
+	root := "."
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		root = path.Clean(prefix[:i])
+	}
+
+	keys := []string{}
+	err := fs.WalkDir(os.DirFS(f.Dir), root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p != root {
+				// skip the subdirectory that can't be read
+				return nil
+			}
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		if d.IsDir() {
+			if root == "." || strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		// skip hidden files, such as the temporary files of writeFileAtomic
+		if strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		if strings.HasPrefix(p, prefix) {
+			keys = append(keys, p)
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory, syncs
+// it, and renames it over path. A crash leaves either the old or the new file,
+// never a partially written one.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return err
+	}
+
+	// persist the rename itself. Directories can't be synced on Windows.
+	if runtime.GOOS != "windows" {
+		if d, err := os.Open(dir); err == nil {
+			d.Sync()
+			d.Close()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package save
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// HistoryObject is a transaction of the wallet history
+type HistoryObject struct {
+	TXID string `json:"txid"`
+	// Time is the timestamp of the block of the transaction, zero while it is
+	// pending
+	Time time.Time `json:"time,omitzero"`
+	// Amount is in atomic units and includes the fee for outgoing
+	// transactions
+	Amount   uint64 `json:"amount"`
+	Fee      uint64 `json:"fee"`
+	Incoming bool   `json:"incoming,omitempty"`
+	// Height is the height of the block of the transaction, 0 while it is
+	// pending
+	Height uint64 `json:"height"`
+}
+
+// HistoryVersion is the version of the history cache. A cache of another
+// version is discarded, and the history is scanned again.
+const HistoryVersion = 1
+
+// ScanProgress is the progress of the scan of the pages of incoming or
+// outgoing transactions, so that an interrupted scan can be resumed
+type ScanProgress struct {
+	NextPage uint64 `json:"next_page"`
+	MaxPage  uint64 `json:"max_page"`
+	Complete bool   `json:"complete"`
+}
+
+// WalletHistory is the cached transaction history of a wallet
+type WalletHistory struct {
+	Version      int             `json:"version"`
+	Transactions []HistoryObject `json:"transactions"`
+	Outgoing     ScanProgress    `json:"outgoing"`
+	Incoming     ScanProgress    `json:"incoming"`
+}
+
+// The history reveals every transaction of the wallet, so it is encrypted with
+// a key of the wallet and can only be read while the wallet is open.
+func historyKey(name string) string {
+	return "history/" + name + ".enc"
+}
+
+// ReadHistory decrypts the cached transaction history of the wallet, which is
+// empty if the wallet has never been scanned.
+// Note: name is already a PathEscaped string
+func ReadHistory(name string, aead cipher.AEAD) (*WalletHistory, error) {
+	sealed, err := wallets.Get(historyKey(name))
+	if errors.Is(err, ErrNotFound) {
+		return &WalletHistory{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("transaction history is corrupted")
+	}
+	data, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &WalletHistory{}
+	err = json.Unmarshal(data, history)
+	if err != nil {
+		return nil, err
+	}
+	if history.Version != HistoryVersion {
+		return &WalletHistory{}, nil
+	}
+	return history, nil
+}
+
+// SaveHistory encrypts and stores the transaction history of the wallet
+// Note: name is already a PathEscaped string
+func SaveHistory(name string, history *WalletHistory, aead cipher.AEAD) error {
+	history.Version = HistoryVersion
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+	return wallets.Set(historyKey(name), aead.Seal(nonce, nonce, data, nil))
+}
@@ -0,0 +1,54 @@
+package save
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+)
+
+func testCipher(t *testing.T, key byte) cipher.AEAD {
+	block, err := aes.NewCipher(bytes.Repeat([]byte{key}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aead
+}
+
+func TestHistoryIsEncrypted(t *testing.T) {
+	store := NewMemStore()
+	UseStore(store)
+	aead := testCipher(t, 1)
+
+	history := &WalletHistory{
+		Transactions: []HistoryObject{{TXID: "deadbeef", Amount: 123456789, Height: 42}},
+		Outgoing:     ScanProgress{NextPage: 1, Complete: true},
+	}
+	if err := SaveHistory("w", history, aead); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, _ := store.Keys("history/")
+	for _, k := range keys {
+		data, _ := store.Get(k)
+		if bytes.Contains(data, []byte("deadbeef")) || bytes.Contains(data, []byte("123456789")) {
+			t.Errorf("%s contains the history in plain text", k)
+		}
+	}
+
+	got, err := ReadHistory("w", aead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Transactions) != 1 || got.Transactions[0] != history.Transactions[0] || got.Outgoing != history.Outgoing {
+		t.Errorf("history = %+v, want %+v", got, history)
+	}
+
+	if _, err := ReadHistory("w", testCipher(t, 2)); err == nil {
+		t.Error("history decrypted with the key of another wallet")
+	}
+}
@@ -0,0 +1,145 @@
+//go:build js
+// +build js
+
+package save
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"syscall/js"
+
+	"github.com/hack-pad/go-indexeddb/idb"
+)
+
+const idbName = "virel-gui"
+const idbStore = "files"
+
+func init() {
+	db, err := NewIndexedDB()
+	if err != nil {
+		fmt.Println("IndexedDB is not available, using localStorage:", err)
+		UseStore(LocalStorage{})
+		return
+	}
+
+	err = migrateLocalStorage(LocalStorage{}, db)
+	if err != nil {
+		// keep using localStorage rather than losing data
+		fmt.Println("failed to migrate localStorage to IndexedDB:", err)
+		UseStore(LocalStorage{})
+		return
+	}
+	UseStore(db)
+}
+
+// IndexedDB is a Store backed by the browser's IndexedDB. Unlike localStorage,
+// it stores binary values and is not limited to a few megabytes.
+type IndexedDB struct {
+	db *idb.Database
+}
+
+func NewIndexedDB() (*IndexedDB, error) {
+	ctx := context.Background()
+	req, err := idb.Global().Open(ctx, idbName, 1, func(db *idb.Database, oldVer, newVer uint) error {
+		_, err := db.CreateObjectStore(idbStore, idb.ObjectStoreOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	db, err := req.Await(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &IndexedDB{db: db}, nil
+}
+
+func (i *IndexedDB) objectStore(mode idb.TransactionMode) (*idb.Transaction, *idb.ObjectStore, error) {
+	txn, err := i.db.Transaction(mode, idbStore)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := txn.ObjectStore(idbStore)
+	if err != nil {
+		return nil, nil, err
+	}
+	return txn, store, nil
+}
+
+func (i *IndexedDB) Get(key string) ([]byte, error) {
+	_, store, err := i.objectStore(idb.TransactionReadOnly)
+	if err != nil {
+		return nil, err
+	}
+	req, err := store.Get(js.ValueOf(key))
+	if err != nil {
+		return nil, err
+	}
+	v, err := req.Await(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if v.IsUndefined() || v.IsNull() {
+		return nil, &fs.PathError{Op: "get", Path: key, Err: ErrNotFound}
+	}
+
+	data := make([]byte, v.Get("length").Int())
+	js.CopyBytesToGo(data, v)
+	return data, nil
+}
+
+func (i *IndexedDB) Set(key string, data []byte) error {
+	txn, store, err := i.objectStore(idb.TransactionReadWrite)
+	if err != nil {
+		return err
+	}
+
+	v := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(v, data)
+
+	_, err = store.PutKey(js.ValueOf(key), v)
+	if err != nil {
+		return err
+	}
+	// the write is only durable once the transaction completes
+	return txn.Await(context.Background())
+}
+
+func (i *IndexedDB) Delete(key string) error {
+	txn, store, err := i.objectStore(idb.TransactionReadWrite)
+	if err != nil {
+		return err
+	}
+	_, err = store.Delete(js.ValueOf(key))
+	if err != nil {
+		return err
+	}
+	return txn.Await(context.Background())
+}
+
+func (i *IndexedDB) Keys(prefix string) ([]string, error) {
+	_, store, err := i.objectStore(idb.TransactionReadOnly)
+	if err != nil {
+		return nil, err
+	}
+	req, err := store.GetAllKeys()
+	if err != nil {
+		return nil, err
+	}
+	values, err := req.Await(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for _, v := range values {
+		if k := v.String(); strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
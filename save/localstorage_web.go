@@ -0,0 +1,56 @@
+//go:build js
+// +build js
+
+package save
+
+import (
+	"encoding/base64"
+	"io/fs"
+	"slices"
+	"strings"
+	"syscall/js"
+)
+
+// LocalStorage is a Store backed by the browser's localStorage. Values are
+// base64 encoded, since localStorage only holds strings.
+type LocalStorage struct{}
+
+func (LocalStorage) storage() js.Value {
+	return js.Global().Get("localStorage")
+}
+
+func (l LocalStorage) Get(key string) ([]byte, error) {
+	item := l.storage().Call("getItem", key)
+
+	if item.IsNull() {
+		return nil, &fs.PathError{Op: "get", Path: key, Err: ErrNotFound}
+	}
+
+	return base64.URLEncoding.DecodeString(item.String())
+}
+
+func (l LocalStorage) Set(key string, data []byte) error {
+	dataStr := base64.URLEncoding.EncodeToString(data)
+	l.storage().Call("setItem", key, dataStr)
+	return nil
+}
+
+func (l LocalStorage) Delete(key string) error {
+	l.storage().Call("removeItem", key)
+	return nil
+}
+
+func (l LocalStorage) Keys(prefix string) ([]string, error) {
+	localStorage := l.storage()
+	length := localStorage.Get("length").Int()
+
+	keys := []string{}
+	for i := 0; i < length; i++ {
+		key := localStorage.Call("key", i).String()
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
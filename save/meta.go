@@ -0,0 +1,79 @@
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WalletMeta is the metadata kept next to a wallet file
+type WalletMeta struct {
+	// Name is the name shown to the user. Unlike the file name, it can
+	// contain any character.
+	Name        string    `json:"name"`
+	Created     time.Time `json:"created,omitzero"`
+	LastOpened  time.Time `json:"last_opened,omitzero"`
+	LastBalance uint64    `json:"last_balance"`
+	Network     string    `json:"network,omitempty"`
+}
+
+// MaxWalletFileName is the maximum length in bytes of the storage name of a
+// wallet. A character of the display name takes up to 12 bytes once escaped,
+// and file names are limited to 255 bytes, including the extension and the
+// suffix of the temporary files of writeFileAtomic.
+const MaxWalletFileName = 200
+
+// WalletFileName returns the storage name of the wallet with the given display
+// name. Dots are escaped too, so that names like "." or ".hidden" are safe.
+func WalletFileName(displayName string) string {
+	return strings.ReplaceAll(url.PathEscape(displayName), ".", "%2E")
+}
+
+func metaKey(name string) string {
+	return "meta/" + name + ".json"
+}
+
+// ReadWalletMeta returns the metadata of the wallet. Wallets created before
+// metadata existed get default metadata named after the wallet file.
+// Note: name is already a PathEscaped string
+func ReadWalletMeta(name string) (*WalletMeta, error) {
+	data, err := wallets.Get(metaKey(name))
+	if errors.Is(err, ErrNotFound) {
+		displayName, err := url.PathUnescape(name)
+		if err != nil {
+			displayName = name
+		}
+		return &WalletMeta{Name: displayName}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	meta := &WalletMeta{}
+	err = json.Unmarshal(data, meta)
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// Note: name is already a PathEscaped string
+func SaveWalletMeta(name string, meta *WalletMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return wallets.Set(metaKey(name), data)
+}
+
+// UpdateWalletMeta reads the metadata of the wallet, applies f and saves it
+// Note: name is already a PathEscaped string
+func UpdateWalletMeta(name string, f func(meta *WalletMeta)) error {
+	meta, err := ReadWalletMeta(name)
+	if err != nil {
+		return err
+	}
+	f(meta)
+	return SaveWalletMeta(name, meta)
+}
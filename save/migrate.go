@@ -0,0 +1,51 @@
+package save
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// migrateLocalStorage moves the wallets, backups and settings saved by older
+// versions from localStorage to dst. Each item is removed from localStorage
+// only once it has been written to dst.
+func migrateLocalStorage(src, dst Store) error {
+	keys, err := src.Keys("")
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if !isOwnKey(k) {
+			continue
+		}
+		data, err := src.Get(k)
+		if err != nil {
+			return err
+		}
+		fmt.Println("migrating", k, "to IndexedDB")
+		err = dst.Set(k, data)
+		if err != nil {
+			return err
+		}
+		err = src.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ownPrefixes are the prefixes of the keys written by this package, besides
+// the wallets and the settings
+var ownPrefixes = []string{"meta/", "history/", "trash/", "backups/"}
+
+// isOwnKey reports whether a localStorage key was written by this package, as
+// other parts of the app may use localStorage too
+func isOwnKey(k string) bool {
+	if slices.Contains(settingsFiles, k) || strings.HasSuffix(k, ".keys") {
+		return true
+	}
+	return slices.ContainsFunc(ownPrefixes, func(p string) bool {
+		return strings.HasPrefix(k, p)
+	})
+}
@@ -0,0 +1,46 @@
+package save
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMigrateLocalStorage(t *testing.T) {
+	own := []string{
+		settingsFile,
+		"rpc-urls.txt",
+		"wallet.keys",
+		"meta/wallet.json",
+		"history/wallet.enc",
+		"trash/wallet/20250101-000000.000000000.keys",
+		"trash/wallet/20250101-000000.000000000.history.enc",
+		"backups/wallet/20250101-000000.000000000.keys",
+	}
+	other := []string{"fyne-preferences", "wallet.json", "metadata"}
+
+	src := NewMemStore()
+	for _, k := range append(slices.Clone(own), other...) {
+		src.Set(k, []byte(k))
+	}
+	dst := NewMemStore()
+	if err := migrateLocalStorage(src, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, k := range own {
+		if data, err := dst.Get(k); err != nil || string(data) != k {
+			t.Errorf("%s was not migrated: %q, %v", k, data, err)
+		}
+		if _, err := src.Get(k); err == nil {
+			t.Errorf("%s was not removed from localStorage", k)
+		}
+	}
+	for _, k := range other {
+		if _, err := dst.Get(k); err == nil {
+			t.Errorf("%s is not ours but was migrated", k)
+		}
+		if _, err := src.Get(k); err != nil {
+			t.Errorf("%s is not ours but was removed from localStorage", k)
+		}
+	}
+}
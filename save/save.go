@@ -0,0 +1,55 @@
+package save
+
+import (
+	"strings"
+)
+
+// settingsFiles are the keys of the settings store, including the legacy ones
+// that are migrated to settings.toml
+var settingsFiles = []string{settingsFile, "rpc-urls.txt", "proxy.txt", "node-auth.bin", "node-auth.key"}
+
+func GetWallets() ([]string, error) {
+	keys, err := wallets.Keys("")
+	if err != nil {
+		return nil, err
+	}
+	var walls = []string{}
+	for _, v := range keys {
+		if strings.Contains(v, "/") || len(v) < 6 || !strings.HasSuffix(v, ".keys") {
+			continue
+		}
+		walls = append(walls, strings.TrimSuffix(v, ".keys"))
+	}
+	return walls, nil
+}
+
+// Note: name is already a PathEscaped string
+func ReadWallet(name string) ([]byte, error) {
+	return wallets.Get(name + ".keys")
+}
+
+// SaveWallet replaces the wallet, keeping a backup of the previous version.
+// Note: name is already a PathEscaped string
+func SaveWallet(name string, data []byte) error {
+	err := backupWallet(name)
+	if err != nil {
+		return err
+	}
+	return wallets.Set(name+".keys", data)
+}
+
+// SaveNodeAuth stores the encrypted node credentials
+func SaveNodeAuth(data []byte) error {
+	return settings.Set("node-auth.bin", data)
+}
+func ReadNodeAuth() ([]byte, error) {
+	return settings.Get("node-auth.bin")
+}
+
+// SaveNodeAuthKey stores the key used to encrypt the node credentials
+func SaveNodeAuthKey(data []byte) error {
+	return settings.Set("node-auth.key", data)
+}
+func ReadNodeAuthKey() ([]byte, error) {
+	return settings.Get("node-auth.key")
+}
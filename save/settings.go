@@ -0,0 +1,136 @@
+package save
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// SettingsVersion is the version of the settings document written by this
+// version of the app. Older documents are migrated when loaded.
+const SettingsVersion = 1
+
+const settingsFile = "settings.toml"
+
+type Settings struct {
+	Version int `toml:"version"`
+
+	// Language is the language tag chosen by the user
+	Language string `toml:"language,omitempty"`
+	// Theme is "light", "dark", or empty to follow the system
+	Theme string `toml:"theme,omitempty"`
+	// Proxy is the URL of the proxy used for all network traffic
+	Proxy string `toml:"proxy,omitempty"`
+	// Nodes is the node list, in the format of the old rpc-urls.txt
+	Nodes string `toml:"nodes,omitempty"`
+
+	Window WindowSettings `toml:"window"`
+}
+
+type WindowSettings struct {
+	Width  float32 `toml:"width,omitempty"`
+	Height float32 `toml:"height,omitempty"`
+}
+
+// migrations[v] upgrades a settings document from version v to v+1. Version 0
+// means no settings document exists yet.
+var migrations = []func(s *Settings) error{
+	migrateLegacySettings,
+}
+
+// migrateLegacySettings imports the plain-text files used before the settings
+// document existed. They are left in place so older versions keep working.
+func migrateLegacySettings(s *Settings) error {
+	rpcUrls, err := settings.Get("rpc-urls.txt")
+	if err == nil {
+		s.Nodes = strings.TrimSpace(string(rpcUrls))
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	proxy, err := settings.Get("proxy.txt")
+	if err == nil {
+		s.Proxy = strings.TrimSpace(string(proxy))
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+var settingsMut sync.Mutex
+
+// LoadSettings reads the settings document, migrating it to the current
+// version if needed
+func LoadSettings() (*Settings, error) {
+	settingsMut.Lock()
+	defer settingsMut.Unlock()
+
+	return loadSettings()
+}
+
+func loadSettings() (*Settings, error) {
+	s := &Settings{}
+
+	data, err := settings.Get(settingsFile)
+	if err == nil {
+		_, err = toml.Decode(string(data), s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid settings file: %w", err)
+		}
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if s.Version > SettingsVersion {
+		// written by a newer version, unknown fields are ignored
+		fmt.Println("settings file version", s.Version, "is newer than", SettingsVersion)
+		return s, nil
+	}
+	if s.Version == SettingsVersion {
+		return s, nil
+	}
+
+	for s.Version < SettingsVersion {
+		fmt.Println("migrating settings from version", s.Version)
+		err = migrations[s.Version](s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate settings from version %d: %w", s.Version, err)
+		}
+		s.Version++
+	}
+	return s, saveSettings(s)
+}
+
+// SaveSettings replaces the settings document
+func SaveSettings(s *Settings) error {
+	settingsMut.Lock()
+	defer settingsMut.Unlock()
+
+	return saveSettings(s)
+}
+
+func saveSettings(s *Settings) error {
+	buf := &bytes.Buffer{}
+	err := toml.NewEncoder(buf).Encode(s)
+	if err != nil {
+		return err
+	}
+	return settings.Set(settingsFile, buf.Bytes())
+}
+
+// UpdateSettings loads the settings, applies f and saves them
+func UpdateSettings(f func(s *Settings)) error {
+	settingsMut.Lock()
+	defer settingsMut.Unlock()
+
+	s, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	f(s)
+	return saveSettings(s)
+}
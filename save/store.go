@@ -0,0 +1,85 @@
+package save
+
+import (
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned by Store.Get when the key does not exist
+var ErrNotFound = fs.ErrNotExist
+
+// Store is a key-value storage backend. Keys are slash-separated paths, such
+// as "wallet.keys" or "backups/wallet/20250101-000000.000000000.keys".
+type Store interface {
+	Get(key string) ([]byte, error)
+	Set(key string, data []byte) error
+	Delete(key string) error
+	// Keys returns every key starting with prefix
+	Keys(prefix string) ([]string, error)
+}
+
+// wallets holds the wallet files and their backups, settings holds everything
+// else. They are the same store on the web.
+var wallets, settings Store
+
+// UseStore makes both the wallets and the settings use s. It is meant for
+// tests, with a MemStore.
+func UseStore(s Store) {
+	wallets = s
+	settings = s
+}
+
+// MemStore is an in-memory Store
+type MemStore struct {
+	mut  sync.RWMutex
+	data map[string][]byte
+}
+
+func NewMemStore() *MemStore {
+	return &MemStore{
+		data: make(map[string][]byte),
+	}
+}
+
+func (m *MemStore) Get(key string) ([]byte, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	v, ok := m.data[key]
+	if !ok {
+		return nil, &fs.PathError{Op: "get", Path: key, Err: ErrNotFound}
+	}
+	return slices.Clone(v), nil
+}
+
+func (m *MemStore) Set(key string, data []byte) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	m.data[key] = slices.Clone(data)
+	return nil
+}
+
+func (m *MemStore) Delete(key string) error {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	delete(m.data, key)
+	return nil
+}
+
+func (m *MemStore) Keys(prefix string) ([]string, error) {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+
+	keys := []string{}
+	for _, k := range slices.Sorted(maps.Keys(m.data)) {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
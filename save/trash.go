@@ -0,0 +1,209 @@
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrWalletExists = errors.New("wallet already exists")
+
+// moveKey copies the value of a key to another key, then deletes the original
+func moveKey(s Store, from, to string) error {
+	data, err := s.Get(from)
+	if err != nil {
+		return err
+	}
+	err = s.Set(to, data)
+	if err != nil {
+		return err
+	}
+	return s.Delete(from)
+}
+
+// moveOptional moves a key like moveKey, but does nothing if it doesn't exist.
+// It is used for the data kept next to a wallet file, such as its metadata.
+func moveOptional(from, to string) error {
+	err := moveKey(wallets, from, to)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// moveBackups moves the backup files found directly under the from prefix to
+// the to prefix
+func moveBackups(from, to string) error {
+	keys, err := wallets.Keys(from)
+	if err != nil {
+		return err
+	}
+	for _, v := range keys {
+		backup := strings.TrimPrefix(v, from)
+		if strings.Contains(backup, "/") || !strings.HasSuffix(backup, ".keys") {
+			continue
+		}
+		err = moveKey(wallets, v, to+backup)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walletExists(name string) (bool, error) {
+	_, err := wallets.Get(name + ".keys")
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// RenameWallet renames the wallet, its backups, its metadata and its history
+// Note: oldName is already a PathEscaped string
+func RenameWallet(oldName, newDisplayName string) error {
+	meta, err := ReadWalletMeta(oldName)
+	if err != nil {
+		return err
+	}
+	meta.Name = newDisplayName
+
+	newName := WalletFileName(newDisplayName)
+	if newName == oldName {
+		// only the display name changed
+		return SaveWalletMeta(oldName, meta)
+	}
+
+	exists, err := walletExists(newName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrWalletExists, newDisplayName)
+	}
+
+	err = moveKey(wallets, oldName+".keys", newName+".keys")
+	if err != nil {
+		return err
+	}
+
+	err = moveBackups(backupPrefix(oldName), backupPrefix(newName))
+	if err != nil {
+		return err
+	}
+
+	err = moveOptional(historyKey(oldName), historyKey(newName))
+	if err != nil {
+		return err
+	}
+
+	err = SaveWalletMeta(newName, meta)
+	if err != nil {
+		return err
+	}
+	return wallets.Delete(metaKey(oldName))
+}
+
+// TrashedWallet is a deleted wallet that can still be restored
+type TrashedWallet struct {
+	Name        string
+	DisplayName string
+	Deleted     time.Time
+
+	id string
+}
+
+func trashPrefix(name string) string {
+	return "trash/" + name + "/"
+}
+
+// DeleteWallet moves the wallet to the trash, along with its backups so that a
+// new wallet with the same name doesn't inherit them
+// Note: name is already a PathEscaped string
+func DeleteWallet(name string) error {
+	id := time.Now().UTC().Format(backupTimeFormat)
+	err := moveBackups(backupPrefix(name), trashPrefix(name)+id+"/")
+	if err != nil {
+		return err
+	}
+	err = moveOptional(metaKey(name), trashPrefix(name)+id+".json")
+	if err != nil {
+		return err
+	}
+	err = moveOptional(historyKey(name), trashPrefix(name)+id+".history.enc")
+	if err != nil {
+		return err
+	}
+	return moveKey(wallets, name+".keys", trashPrefix(name)+id+".keys")
+}
+
+// ListTrash returns the deleted wallets, most recently deleted first
+func ListTrash() ([]TrashedWallet, error) {
+	keys, err := wallets.Keys("trash/")
+	if err != nil {
+		return nil, err
+	}
+
+	trashed := []TrashedWallet{}
+	for _, v := range keys {
+		name, id, ok := strings.Cut(strings.TrimPrefix(v, "trash/"), "/")
+		// the backups of the deleted wallets are in subdirectories
+		if !ok || strings.Contains(id, "/") || !strings.HasSuffix(id, ".keys") {
+			continue
+		}
+		id = strings.TrimSuffix(id, ".keys")
+		deleted, err := time.Parse(backupTimeFormat, id)
+		if err != nil {
+			continue
+		}
+		t := TrashedWallet{
+			Name:        name,
+			DisplayName: name,
+			Deleted:     deleted,
+			id:          id,
+		}
+		if n, err := url.PathUnescape(name); err == nil {
+			t.DisplayName = n
+		}
+		data, err := wallets.Get(trashPrefix(name) + id + ".json")
+		if err == nil {
+			meta := &WalletMeta{}
+			if json.Unmarshal(data, meta) == nil && meta.Name != "" {
+				t.DisplayName = meta.Name
+			}
+		}
+		trashed = append(trashed, t)
+	}
+	slices.SortFunc(trashed, func(a, b TrashedWallet) int {
+		return b.Deleted.Compare(a.Deleted)
+	})
+	return trashed, nil
+}
+
+// RestoreFromTrash puts a deleted wallet back under its original name
+func RestoreFromTrash(t TrashedWallet) error {
+	exists, err := walletExists(t.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrWalletExists, t.Name)
+	}
+	err = moveOptional(trashPrefix(t.Name)+t.id+".json", metaKey(t.Name))
+	if err != nil {
+		return err
+	}
+	err = moveOptional(trashPrefix(t.Name)+t.id+".history.enc", historyKey(t.Name))
+	if err != nil {
+		return err
+	}
+	err = moveBackups(trashPrefix(t.Name)+t.id+"/", backupPrefix(t.Name))
+	if err != nil {
+		return err
+	}
+	return moveKey(wallets, trashPrefix(t.Name)+t.id+".keys", t.Name+".keys")
+}
@@ -0,0 +1,64 @@
+package save
+
+import "testing"
+
+func TestTrashKeepsBackupsWithTheWallet(t *testing.T) {
+	UseStore(NewMemStore())
+
+	mustSave := func(name, data string) {
+		t.Helper()
+		if err := SaveWallet(name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustSave("w", "v1")
+	mustSave("w", "v2")
+	if b, _ := ListBackups("w"); len(b) != 1 {
+		t.Fatalf("backups = %v, want 1", b)
+	}
+
+	if err := DeleteWallet("w"); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ListBackups("w"); len(b) != 0 {
+		t.Fatalf("backups of the deleted wallet = %v, want none", b)
+	}
+
+	// a new wallet with the same name doesn't inherit the backups
+	mustSave("w", "other")
+	if b, _ := ListBackups("w"); len(b) != 0 {
+		t.Fatalf("backups of the new wallet = %v, want none", b)
+	}
+	if err := DeleteWallet("w"); err != nil {
+		t.Fatal(err)
+	}
+
+	trash, err := ListTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 2 {
+		t.Fatalf("trash = %v, want 2 wallets", trash)
+	}
+	// the first deleted wallet is listed last
+	if err := RestoreFromTrash(trash[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ReadWallet("w")
+	if err != nil || string(data) != "v2" {
+		t.Fatalf("restored wallet = %q, %v", data, err)
+	}
+	backups, err := ListBackups("w")
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("restored backups = %v, %v", backups, err)
+	}
+	data, err = ReadBackup("w", backups[0])
+	if err != nil || string(data) != "v1" {
+		t.Errorf("restored backup = %q, %v", data, err)
+	}
+
+	if trash, _ := ListTrash(); len(trash) != 1 {
+		t.Errorf("trash after restore = %v, want 1 wallet", trash)
+	}
+}
@@ -0,0 +1,219 @@
+//go:build !js
+// +build !js
+
+package save
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const appDirName = "virel-gui"
+
+// walletDir is where the .keys files are stored. It defaults to the working
+// directory until SetWalletDir is called.
+var walletDir = "."
+
+// DefaultWalletDir returns the per-OS wallet directory: $XDG_DATA_HOME (or
+// ~/.local/share) on Linux and other Unix systems, the application support
+// directory on macOS and %AppData% on Windows.
+func DefaultWalletDir() (string, error) {
+	var base string
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "android":
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		base = dir
+	default:
+		base = os.Getenv("XDG_DATA_HOME")
+		if base == "" || !filepath.IsAbs(base) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			base = filepath.Join(home, ".local", "share")
+		}
+	}
+	return filepath.Join(base, appDirName, "wallets"), nil
+}
+
+// configDir is where the settings that must be found regardless of the
+// working directory are stored
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appDirName), nil
+}
+
+// SetWalletDir changes the wallet directory, creating it if needed
+func SetWalletDir(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return err
+	}
+	walletDir = dir
+	wallets = &FileStore{Dir: dir}
+	return nil
+}
+
+func WalletDir() string {
+	return walletDir
+}
+
+// SaveWalletDirSetting persists the wallet directory chosen by the user. An
+// empty dir restores the default.
+func SaveWalletDirSetting(dir string) error {
+	cfg, err := configDir()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(cfg, 0o700)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(cfg, "wallet-dir.txt"), []byte(dir), 0o660)
+}
+func ReadWalletDirSetting() (string, error) {
+	cfg, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(cfg, "wallet-dir.txt"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// MigrateWallets moves the .keys files found in oldDir to the wallet directory.
+// It only runs once per wallet directory, and never overwrites a wallet.
+func MigrateWallets(oldDir string) error {
+	oldDir, err := filepath.Abs(oldDir)
+	if err != nil {
+		return err
+	}
+	if oldDir == walletDir {
+		return nil
+	}
+
+	marker := filepath.Join(walletDir, ".migrated")
+	if _, err := os.Stat(marker); err == nil {
+		return nil
+	}
+
+	entries, err := os.ReadDir(oldDir)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, v := range entries {
+		if v.IsDir() || !strings.HasSuffix(v.Name(), ".keys") {
+			continue
+		}
+		dst := filepath.Join(walletDir, v.Name())
+		if _, err := os.Stat(dst); err == nil {
+			errs = append(errs, fmt.Errorf("not migrating %s: wallet already exists", v.Name()))
+			continue
+		}
+		fmt.Println("migrating wallet", v.Name(), "to", walletDir)
+		err = moveFile(filepath.Join(oldDir, v.Name()), dst)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	err = os.WriteFile(marker, []byte(oldDir), 0o600)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// MigrateSettings moves the settings files found in oldDir, which used to be
+// kept in the working directory, to the settings directory. Files that already
+// exist in the settings directory are left in oldDir.
+func MigrateSettings(oldDir string) error {
+	fstore, ok := settings.(*FileStore)
+	if !ok {
+		return nil
+	}
+	oldDir, err := filepath.Abs(oldDir)
+	if err != nil {
+		return err
+	}
+	dir, err := filepath.Abs(fstore.Dir)
+	if err != nil {
+		return err
+	}
+	if oldDir == dir {
+		return nil
+	}
+
+	var errs []error
+	for _, v := range settingsFiles {
+		src := filepath.Join(oldDir, v)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		dst := filepath.Join(dir, v)
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		err = os.MkdirAll(dir, 0o700)
+		if err != nil {
+			return err
+		}
+		fmt.Println("migrating", v, "to", dir)
+		err = moveFile(src, dst)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// moveFile renames src to dst, falling back to a copy when they are on
+// different filesystems
+func moveFile(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o660)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
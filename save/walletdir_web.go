@@ -0,0 +1,36 @@
+//go:build js
+// +build js
+
+package save
+
+import "errors"
+
+// Wallets are kept in the browser storage on the web build, so the wallet
+// directory functions are no-ops.
+
+func DefaultWalletDir() (string, error) {
+	return "", nil
+}
+
+func SetWalletDir(dir string) error {
+	return nil
+}
+
+func WalletDir() string {
+	return ""
+}
+
+func SaveWalletDirSetting(dir string) error {
+	return errors.New("wallet directory is not supported on the web")
+}
+func ReadWalletDirSetting() (string, error) {
+	return "", errors.New("wallet directory is not supported on the web")
+}
+
+func MigrateWallets(oldDir string) error {
+	return nil
+}
+
+func MigrateSettings(oldDir string) error {
+	return nil
+}
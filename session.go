@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/virel-project/virel-blockchain/v3/wallet"
+)
+
+// Session is an open wallet. Its context is cancelled when the wallet is
+// closed, which stops every goroutine started with Go.
+type Session struct {
+	Wallet *wallet.Wallet
+	// Name is the PathEscaped name of the wallet file
+	Name string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var sessionMut sync.Mutex
+var session *Session
+
+// OpenSession closes the current session, if any, and starts a new one for
+// the wallet
+func OpenSession(wall *wallet.Wallet, name string) *Session {
+	CloseSession()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Session{
+		Wallet: wall,
+		Name:   name,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	sessionMut.Lock()
+	session = s
+	sessionMut.Unlock()
+
+	return s
+}
+
+// CloseSession closes the current session and waits for its goroutines to
+// return
+func CloseSession() {
+	sessionMut.Lock()
+	s := session
+	session = nil
+	sessionMut.Unlock()
+
+	if s != nil {
+		s.Close()
+	}
+}
+
+func (s *Session) Context() context.Context {
+	return s.ctx
+}
+
+// Go runs f in a goroutine that is tracked by the session
+func (s *Session) Go(f func(ctx context.Context)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		f(s.ctx)
+	}()
+}
+
+// Close cancels the session and waits for its goroutines to return
+func (s *Session) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
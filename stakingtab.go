@@ -14,8 +14,8 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 	"github.com/virel-project/virel-blockchain/v3/config"
-	"github.com/virel-project/virel-blockchain/v3/util"
 	"github.com/virel-project/virel-blockchain/v3/wallet"
+	"github.com/virel-project/virel-gui/v2/lang"
 	"github.com/virel-project/virel-gui/v2/mywidget"
 )
 
@@ -37,9 +37,9 @@ func CreateStakingTab(wall *wallet.Wallet) *StakingTab {
 	}
 
 	st.StakedBalance = mywidget.NewCard(a, theme.Color(theme.ColorNamePrimary),
-		util.FormatCoin(wall.GetStakedBalance()), T.StakedBalance, T.StakedBalanceCopied)
+		formatCoin(wall.GetStakedBalance()), T.StakedBalance, T.StakedBalanceCopied)
 
-	delegateStr := "none"
+	delegateStr := T.DelegateNone
 	if wall.GetDelegateId() != 0 {
 		delegateStr = strconv.FormatUint(wall.GetDelegateId(), 10)
 	}
@@ -53,12 +53,8 @@ func CreateStakingTab(wall *wallet.Wallet) *StakingTab {
 	st.UnstakeBtn = widget.NewButton(T.Unstake, st.UnstakeBtnClicked)
 
 	stakedUnlockHeight := wall.GetStakedUnlock()
-	remainingTime := ""
-	if stakedUnlockHeight > wall.GetHeight() {
-		remainingTime = (time.Duration(stakedUnlockHeight-wall.GetHeight()) * time.Second * config.TARGET_BLOCK_TIME).String()
-	}
 	st.UnlockTime = mywidget.NewCard(a, theme.Color(theme.ColorNameButton),
-		strconv.FormatUint(stakedUnlockHeight, 10), fmt.Sprintf(T.StakedUnlockHeight, remainingTime), T.StakedUnlockCopied)
+		strconv.FormatUint(stakedUnlockHeight, 10), st.unlockComment(), T.StakedUnlockCopied)
 	if stakedUnlockHeight > st.Wallet.GetHeight() {
 		st.UnlockTime.Show()
 	} else {
@@ -68,36 +64,46 @@ func CreateStakingTab(wall *wallet.Wallet) *StakingTab {
 	return st
 }
 
-func (s *StakingTab) Update() {
-	fyne.Do(func() {
-		s.StakedBalance.SetTitle(util.FormatCoin(s.Wallet.GetStakedBalance()))
+// unlockComment describes how long the staked balance stays locked
+func (s *StakingTab) unlockComment() string {
+	var blocks uint64
+	if unlock, height := s.Wallet.GetStakedUnlock(), s.Wallet.GetHeight(); unlock > height {
+		blocks = unlock - height
+	}
+	remainingTime := time.Duration(blocks) * time.Second * config.TARGET_BLOCK_TIME
 
-		stakedUnlockHeight := s.Wallet.GetStakedUnlock()
-		remainingTime := ""
-		if stakedUnlockHeight > s.Wallet.GetHeight() {
-			remainingTime = (time.Duration(stakedUnlockHeight-s.Wallet.GetHeight()) * time.Second * config.TARGET_BLOCK_TIME).String()
-		}
-		s.UnlockTime.SetTitle(strconv.FormatUint(stakedUnlockHeight, 10))
-		s.UnlockTime.SetComment(fmt.Sprintf(T.StakedUnlockHeight, remainingTime))
+	return T.StakedUnlockHeight.Format(lang.Args{
+		"count": blocks,
+		"time":  remainingTime.String(),
+	})
+}
 
-		if stakedUnlockHeight > s.Wallet.GetHeight() {
-			s.UnlockTime.Show()
-		} else {
-			s.UnlockTime.Hide()
-		}
+// Refresh updates the cards with the wallet state. It must be called from the
+// UI goroutine.
+func (s *StakingTab) Refresh() {
+	s.StakedBalance.SetTitle(formatCoin(s.Wallet.GetStakedBalance()))
 
-		delegateStr := "none"
-		if s.Wallet.GetDelegateId() != 0 {
-			delegateStr = strconv.FormatUint(s.Wallet.GetDelegateId(), 10)
-		}
-		s.DelegateId.SetTitle(delegateStr)
-	})
+	stakedUnlockHeight := s.Wallet.GetStakedUnlock()
+	s.UnlockTime.SetTitle(strconv.FormatUint(stakedUnlockHeight, 10))
+	s.UnlockTime.SetComment(s.unlockComment())
+
+	if stakedUnlockHeight > s.Wallet.GetHeight() {
+		s.UnlockTime.Show()
+	} else {
+		s.UnlockTime.Hide()
+	}
+
+	delegateStr := T.DelegateNone
+	if s.Wallet.GetDelegateId() != 0 {
+		delegateStr = strconv.FormatUint(s.Wallet.GetDelegateId(), 10)
+	}
+	s.DelegateId.SetTitle(delegateStr)
 }
 
 func (s *StakingTab) SetDelegateBtnClicked() {
 	delegateid := widget.NewEntry()
 	formItems := []*widget.FormItem{
-		{Text: T.DelegateId, Widget: delegateid, HintText: "Find a delegate here: https://explorer.virel.org/delegates"},
+		{Text: T.DelegateId, Widget: delegateid, HintText: T.DelegateHint},
 	}
 
 	delegateid.Validator = func(s string) error {
@@ -142,12 +148,12 @@ func (s *StakingTab) StakeBtnClicked() {
 		{Text: T.TransferAmount, Widget: amount},
 	}
 	amount.Validator = func(s string) error {
-		n, err := strconv.ParseFloat(s, 64)
+		amt, err := parseAmount(s)
 		if err != nil {
-			return errors.New(T.InvalidAmount)
+			return amountError(err)
 		}
-		if n < config.MIN_STAKE_AMOUNT/config.COIN {
-			return fmt.Errorf("minimum stake amount is %v", config.MIN_STAKE_AMOUNT/config.COIN)
+		if amt < config.MIN_STAKE_AMOUNT {
+			return fmt.Errorf(T.ErrMinStakeAmount, formatCoin(config.MIN_STAKE_AMOUNT))
 		}
 		return nil
 	}
@@ -158,25 +164,23 @@ func (s *StakingTab) StakeBtnClicked() {
 
 		fmt.Println("staking...")
 
-		floatAmt, err := strconv.ParseFloat(amount.Text, 64)
+		amt, err := parseAmount(amount.Text)
 		if err != nil {
 			fmt.Println(err)
-			dialog.NewError(err, w).Show()
+			dialog.NewError(amountError(err), w).Show()
 			return
 		}
 
-		amt := uint64(floatAmt * config.COIN)
-
 		if amt < config.MIN_STAKE_AMOUNT {
 			fmt.Println("stake amount too small")
-			dialog.NewError(fmt.Errorf("amount < min stake amount"), w).Show()
+			dialog.NewError(fmt.Errorf(T.ErrMinStakeAmount, formatCoin(config.MIN_STAKE_AMOUNT)), w).Show()
 			return
 		}
 
 		txn, err := s.Wallet.Stake(s.Wallet.GetDelegateId(), amt, s.Wallet.GetStakedUnlock())
 		if err != nil {
 			fmt.Println(err)
-			dialog.NewError(fmt.Errorf("failed to create stake transaction: %w", err), w).Show()
+			dialog.NewError(fmt.Errorf(T.FailedToCreateStakeTx, err), w).Show()
 			return
 		}
 
@@ -187,7 +191,7 @@ func (s *StakingTab) StakeBtnClicked() {
 			return
 		}
 
-		dialog.NewCustomConfirm(T.ConfirmTransfer, T.Confirm, T.Cancel, widget.NewLabel(fmt.Sprintf(T.ConfirmStake, util.FormatCoin(totalAmt))), func(b bool) {
+		dialog.NewCustomConfirm(T.ConfirmTransfer, T.Confirm, T.Cancel, widget.NewLabel(T.ConfirmStake.Format(lang.Args{"amount": formatCoin(totalAmt)})), func(b bool) {
 			if !b {
 				return
 			}
@@ -210,12 +214,12 @@ func (s *StakingTab) UnstakeBtnClicked() {
 		{Text: T.TransferAmount, Widget: amount},
 	}
 	amount.Validator = func(s string) error {
-		n, err := strconv.ParseFloat(s, 64)
+		amt, err := parseAmount(s)
 		if err != nil {
-			return errors.New(T.InvalidAmount)
+			return amountError(err)
 		}
-		if n <= 0 {
-			return fmt.Errorf("invalid unstake amount")
+		if amt == 0 {
+			return errors.New(T.ErrInvalidUnstakeAmount)
 		}
 		return nil
 	}
@@ -223,22 +227,21 @@ func (s *StakingTab) UnstakeBtnClicked() {
 		if !b {
 			return
 		}
-		floatAmt, err := strconv.ParseFloat(amount.Text, 64)
+		amt, err := parseAmount(amount.Text)
 		if err != nil {
 			fmt.Println(err)
+			dialog.NewError(amountError(err), w).Show()
 			return
 		}
 
-		amt := uint64(floatAmt * config.COIN)
-
 		if amt == 0 {
-			dialog.NewError(fmt.Errorf("amount can't be zero"), w).Show()
+			dialog.NewError(errors.New(T.ErrAmountZero), w).Show()
 			return
 		}
 
 		txn, err := s.Wallet.Unstake(s.Wallet.GetDelegateId(), amt)
 		if err != nil {
-			dialog.NewError(fmt.Errorf("failed to create stake transaction: %w", err), w).Show()
+			dialog.NewError(fmt.Errorf(T.FailedToCreateStakeTx, err), w).Show()
 			return
 		}
 
@@ -249,7 +252,7 @@ func (s *StakingTab) UnstakeBtnClicked() {
 			return
 		}
 
-		dialog.NewCustomConfirm(T.ConfirmTransfer, T.Confirm, T.Cancel, widget.NewLabel(fmt.Sprintf(T.ConfirmUnstake, util.FormatCoin(totalAmt))), func(b bool) {
+		dialog.NewCustomConfirm(T.ConfirmTransfer, T.Confirm, T.Cancel, widget.NewLabel(T.ConfirmUnstake.Format(lang.Args{"amount": formatCoin(totalAmt)})), func(b bool) {
 			if !b {
 				return
 			}
@@ -0,0 +1,9 @@
+package main
+
+import "net/http"
+
+// baseTransport is the transport used by all the HTTP traffic of the GUI,
+// including the wallet RPC calls and the update checker. It is captured before
+// http.DefaultTransport is wrapped. Nodes with custom trust settings use clones
+// of it, see tlsTransport.
+var baseTransport = http.DefaultTransport.(*http.Transport)
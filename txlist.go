@@ -2,107 +2,350 @@ package main
 
 import (
 	"cmp"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"slices"
+	"sync"
 	"time"
 
-	"github.com/virel-project/virel-blockchain/v3/config"
+	"github.com/virel-project/virel-blockchain/v3/util"
 	"github.com/virel-project/virel-blockchain/v3/wallet"
+	"github.com/virel-project/virel-gui/v2/save"
 )
 
-type HistoryObject struct {
-	TXID   string
-	Time   time.Time
-	Amount float64
-	Fee    float64
-	Height uint64
+// TxList is the transaction history of a wallet. It is refreshed from a
+// background goroutine and read from the UI goroutine.
+type TxList struct {
+	// OnProgress is called from the refreshing goroutine after each scanned
+	// page
+	OnProgress func()
+
+	mut   sync.RWMutex
+	name  string
+	aead  cipher.AEAD // encrypts the cached history, nil if it is not cached
+	list  []save.HistoryObject
+	index map[string]int // position of each TXID in list
+
+	blockTimes map[uint64]time.Time // only used by the refreshing goroutine
+
+	outgoing save.ScanProgress
+	incoming save.ScanProgress
 }
 
-type TxList struct {
-	List      []HistoryObject
-	refreshed bool
+// txSource is the part of the wallet that the transaction list is scanned
+// from. It is implemented by *wallet.Wallet.
+type txSource interface {
+	GetTransactions(inc bool, page uint64) (*wallet.TxList, error)
+	GetTransaction(txid util.Hash) (*wallet.TxInfo, error)
+	// GetRpcDaemonAddress is the node queried for the block timestamps
+	GetRpcDaemonAddress() string
 }
 
-func (t *TxList) Refresh(wall *wallet.Wallet) error {
-	height := wall.GetHeight()
-	err := t.refreshTxs(wall, height, false, !t.refreshed, 0)
+// historyCipher returns the cipher of the cached history of the wallet. Its key
+// is derived from the seed, so the history can't be read without the wallet
+// password.
+func historyCipher(wall *wallet.Wallet) (cipher.AEAD, error) {
+	mnemonic := wall.GetMnemonic()
+	if mnemonic == "" {
+		return nil, errors.New("the wallet has no seed")
+	}
+	key := sha256.Sum256([]byte("virel-gui transaction history\n" + mnemonic))
+	block, err := aes.NewCipher(key[:])
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewTxList returns the transaction list of the wallet, starting from its
+// cached history so that it can be shown before the first refresh. The history
+// is not cached when aead is nil.
+// Note: name is already a PathEscaped string
+func NewTxList(name string, aead cipher.AEAD) *TxList {
+	t := &TxList{
+		list:       make([]save.HistoryObject, 0),
+		name:       name,
+		aead:       aead,
+		blockTimes: make(map[uint64]time.Time),
+	}
+	if aead == nil {
+		t.reindex()
+		return t
 	}
-	err = t.refreshTxs(wall, height, true, !t.refreshed, 0)
+
+	history, err := save.ReadHistory(name, aead)
 	if err != nil {
-		return err
+		fmt.Println("failed to read transaction history:", err)
+	} else {
+		if history.Transactions != nil {
+			t.list = history.Transactions
+		}
+		t.outgoing = history.Outgoing
+		t.incoming = history.Incoming
+	}
+	t.reindex()
+	for _, v := range t.list {
+		if v.Height != 0 {
+			t.blockTimes[v.Height] = v.Time
+		}
 	}
 
-	t.refreshed = true
+	return t
+}
 
-	return nil
+// Len returns the number of transactions
+func (t *TxList) Len() int {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+	return len(t.list)
+}
+
+// Get returns the i-th transaction, the most recent first
+func (t *TxList) Get(i int) save.HistoryObject {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+	return t.list[i]
+}
+
+// Progress returns the number of pages scanned and the number of pages known
+// so far. done is false until every page has been scanned once.
+func (t *TxList) Progress() (scanned, total uint64, done bool) {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+	for _, v := range []save.ScanProgress{t.outgoing, t.incoming} {
+		scanned += v.NextPage
+		total += v.MaxPage + 1
+	}
+	return scanned, total, t.outgoing.Complete && t.incoming.Complete
+}
+
+func (t *TxList) reindex() {
+	t.index = make(map[string]int, len(t.list))
+	for i, v := range t.list {
+		t.index[v.TXID] = i
+	}
 }
 
-func (t *TxList) refreshTxs(wall *wallet.Wallet, height uint64, inc, fullscan bool, page int) error {
-	txns, err := wall.GetTransactions(inc, 0)
+// blockTime returns the timestamp of the block at the given height, which is
+// the time of all the transactions in it
+func (t *TxList) blockTime(wall txSource, height uint64) (time.Time, error) {
+	if tm, ok := t.blockTimes[height]; ok {
+		return tm, nil
+	}
+	tm, err := GetBlockTime(wall.GetRpcDaemonAddress(), height)
 	if err != nil {
-		return err
+		return time.Time{}, fmt.Errorf("failed to get block %d: %w", height, err)
 	}
+	t.blockTimes[height] = tm
+	return tm, nil
+}
 
-	if fullscan && page < int(txns.MaxPage) {
-		defer func() {
-			t.refreshTxs(wall, height, inc, fullscan, page+1)
-		}()
+// compareTxs orders pending transactions first, then the most recent ones
+func compareTxs(i, j save.HistoryObject) int {
+	if (i.Height == 0) != (j.Height == 0) {
+		if i.Height == 0 {
+			return -1
+		}
+		return 1
 	}
+	return cmp.Compare(j.Height, i.Height)
+}
 
-	updated := false
-	for _, v := range txns.Transactions {
-		strhash := hex.EncodeToString(v[:])
-		notfound := true
-		for _, tx := range t.List {
-			if strhash == tx.TXID {
-				notfound = false
-				break
-			}
+func (t *TxList) save() {
+	if t.aead == nil {
+		return
+	}
+
+	t.mut.RLock()
+	history := &save.WalletHistory{
+		Transactions: t.list,
+		Outgoing:     t.outgoing,
+		Incoming:     t.incoming,
+	}
+	err := save.SaveHistory(t.name, history, t.aead)
+	t.mut.RUnlock()
+	if err != nil {
+		fmt.Println("failed to save transaction history:", err)
+	}
+}
+
+func (t *TxList) progress(inc bool) *save.ScanProgress {
+	if inc {
+		return &t.incoming
+	}
+	return &t.outgoing
+}
+
+// Refresh scans the outgoing and incoming transactions. The list is saved
+// after every scanned page, so an interrupted scan resumes where it stopped.
+func (t *TxList) Refresh(ctx context.Context, wall txSource) error {
+	err := t.scan(ctx, wall, false)
+	if err != nil {
+		return err
+	}
+	return t.scan(ctx, wall, true)
+}
+
+// scan walks the pages of incoming or outgoing transactions. Until every page
+// has been scanned once, it continues from the first page not scanned yet.
+// Afterwards the newest transactions are on the first pages, so it stops at
+// the first page which has a transaction that is already known, unless a
+// pending transaction has not been seen yet: new transactions may have pushed
+// it to a later page.
+func (t *TxList) scan(ctx context.Context, wall txSource, inc bool) error {
+	t.mut.RLock()
+	prog := *t.progress(inc)
+	pending := map[string]bool{}
+	for _, v := range t.list {
+		if v.Incoming == inc && v.Height == 0 {
+			pending[v.TXID] = true
 		}
-		if notfound {
-			tx, err := wall.GetTransaction(v)
+	}
+	t.mut.RUnlock()
 
+	if prog.Complete {
+		for page := uint64(0); ; page++ {
+			txns, err := wall.GetTransactions(inc, page)
+			if err != nil {
+				return err
+			}
+			added, updated, err := t.fetchTxs(ctx, wall, inc, txns.Transactions)
+			if uint64(txns.MaxPage) != prog.MaxPage {
+				prog.MaxPage = uint64(txns.MaxPage)
+				prog.NextPage = prog.MaxPage + 1
+				t.mut.Lock()
+				*t.progress(inc) = prog
+				t.mut.Unlock()
+				updated = true
+			}
+			if updated {
+				t.save()
+			}
 			if err != nil {
-				log.Warn(err)
 				return err
 			}
+			for _, v := range txns.Transactions {
+				delete(pending, hex.EncodeToString(v[:]))
+			}
+
+			if (added < len(txns.Transactions) && len(pending) == 0) || page >= prog.MaxPage {
+				return nil
+			}
+		}
+	}
+
+	for !prog.Complete {
+		txns, err := wall.GetTransactions(inc, prog.NextPage)
+		if err != nil {
+			return err
+		}
+		_, _, err = t.fetchTxs(ctx, wall, inc, txns.Transactions)
+		if err != nil {
+			// the page is scanned again on the next refresh
+			t.save()
+			return err
+		}
+
+		prog.MaxPage = uint64(txns.MaxPage)
+		prog.NextPage++
+		prog.Complete = prog.NextPage > prog.MaxPage
+
+		t.mut.Lock()
+		*t.progress(inc) = prog
+		t.mut.Unlock()
+		t.save()
+
+		if t.OnProgress != nil {
+			t.OnProgress()
+		}
+	}
+
+	return nil
+}
+
+// fetchTxs fetches the transactions that are not in the list yet, and the ones
+// that were not confirmed when they were fetched. It returns the number of new
+// transactions and whether the list changed.
+func (t *TxList) fetchTxs(ctx context.Context, wall txSource, inc bool, hashes []util.Hash) (int, bool, error) {
+	added := 0
+	updated := false
+	defer func() {
+		if updated {
+			fmt.Println("found new transaction, inc:", inc)
+			t.mut.Lock()
+			slices.SortStableFunc(t.list, compareTxs)
+			t.reindex()
+			t.mut.Unlock()
+		}
+	}()
+
+	for _, v := range hashes {
+		strhash := hex.EncodeToString(v[:])
+
+		t.mut.RLock()
+		i, found := t.index[strhash]
+		// confirmed transactions never change, unconfirmed ones are fetched
+		// again until they are in a block
+		known := found && t.list[i].Height != 0
+		t.mut.RUnlock()
+		if known {
+			continue
+		}
 
-			txTime := time.Now().Unix()
+		if err := ctx.Err(); err != nil {
+			return added, updated, err
+		}
+		tx, err := wall.GetTransaction(v)
+		if err != nil {
+			log.Warn(err)
+			return added, updated, err
+		}
 
-			if tx.Height != 0 && tx.Height < height {
-				deltaHeight := int64(height) - int64(tx.Height)
-				txTime -= config.TARGET_BLOCK_TIME * deltaHeight
+		// pending transactions have no time until they are in a block
+		var txTime time.Time
+		if tx.Height != 0 {
+			txTime, err = t.blockTime(wall, tx.Height)
+			if err != nil {
+				return added, updated, err
 			}
+		}
 
-			// outgoing amount
-			amt := -float64(tx.TotalAmount+tx.Fee) / config.COIN
-			// incoming amount
-			if inc {
-				amt = 0
-				for _, v := range tx.Outputs {
-					amt += float64(v.Amount) / config.COIN
-				}
+		// outgoing amount
+		amt := tx.TotalAmount + tx.Fee
+		// incoming amount
+		if inc {
+			amt = 0
+			for _, v := range tx.Outputs {
+				amt += v.Amount
 			}
+		}
 
-			t.List = append(t.List, HistoryObject{
-				TXID:   strhash,
-				Time:   time.Unix(txTime, 0),
-				Amount: amt,
-				Fee:    float64(tx.Fee) / config.COIN,
-				Height: tx.Height,
-			})
+		obj := save.HistoryObject{
+			TXID:     strhash,
+			Time:     txTime,
+			Amount:   amt,
+			Fee:      tx.Fee,
+			Incoming: inc,
+			Height:   tx.Height,
+		}
+
+		t.mut.Lock()
+		if !found {
+			t.index[strhash] = len(t.list)
+			t.list = append(t.list, obj)
+			added++
+			updated = true
+		} else if t.list[i] != obj {
+			t.list[i] = obj
 			updated = true
 		}
-	}
-	if updated {
-		fmt.Println("found new transaction, inc:", inc)
-		slices.SortStableFunc(t.List, func(i, j HistoryObject) int {
-			return cmp.Compare(j.Height, i.Height)
-		})
+		t.mut.Unlock()
 	}
 
-	return nil
+	return added, updated, nil
 }
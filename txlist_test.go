@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/virel-project/virel-blockchain/v3/util"
+	"github.com/virel-project/virel-blockchain/v3/wallet"
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+const fakePageSize = 2
+
+// fakeWallet is a txSource serving pages of fakePageSize transactions, the
+// newest first. Block timestamps are served by a fake node, the block at
+// height h being h seconds after the epoch.
+type fakeWallet struct {
+	rpc string
+
+	mut     sync.Mutex
+	txids   map[bool][]util.Hash // incoming and outgoing, newest first
+	txs     map[util.Hash]*wallet.TxInfo
+	failTx  util.Hash   // GetTransaction of this transaction fails once
+	pages   []uint64    // the outgoing pages requested
+	fetched []util.Hash // the transactions fetched
+}
+
+func newFakeWallet(t *testing.T) *fakeWallet {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params struct {
+				Height uint64
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "get_block_by_height" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		res := blockResponse{}
+		res.Block.Timestamp = req.Params.Height * 1000
+		json.NewEncoder(w).Encode(map[string]any{"result": res})
+	}))
+	t.Cleanup(srv.Close)
+
+	return &fakeWallet{
+		rpc:   srv.URL,
+		txids: map[bool][]util.Hash{},
+		txs:   map[util.Hash]*wallet.TxInfo{},
+	}
+}
+
+// add adds a transaction, which becomes the newest one
+func (f *fakeWallet) add(inc bool, height, amount uint64) util.Hash {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	h := util.Hash{byte(len(f.txs) + 1)}
+	f.txids[inc] = append([]util.Hash{h}, f.txids[inc]...)
+	f.txs[h] = &wallet.TxInfo{Height: height, TotalAmount: amount}
+	return h
+}
+
+func (f *fakeWallet) confirm(h util.Hash, height uint64) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.txs[h].Height = height
+}
+
+// reset forgets the requested pages and transactions
+func (f *fakeWallet) reset() {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	f.pages = nil
+	f.fetched = nil
+}
+
+func (f *fakeWallet) GetTransactions(inc bool, page uint64) (*wallet.TxList, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if !inc {
+		f.pages = append(f.pages, page)
+	}
+	txids := f.txids[inc]
+	start := min(int(page)*fakePageSize, len(txids))
+	end := min(start+fakePageSize, len(txids))
+	list := &wallet.TxList{
+		Transactions: slices.Clone(txids[start:end]),
+	}
+	for i := fakePageSize; i < len(txids); i += fakePageSize {
+		list.MaxPage++
+	}
+	return list, nil
+}
+
+func (f *fakeWallet) GetTransaction(h util.Hash) (*wallet.TxInfo, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if h == f.failTx {
+		f.failTx = util.Hash{}
+		return nil, errors.New("node is down")
+	}
+	f.fetched = append(f.fetched, h)
+	tx := *f.txs[h]
+	return &tx, nil
+}
+
+func (f *fakeWallet) GetRpcDaemonAddress() string {
+	return f.rpc
+}
+
+func testHistoryCipher(t *testing.T) cipher.AEAD {
+	block, err := aes.NewCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aead
+}
+
+func txids(t *TxList) []string {
+	ids := make([]string, t.Len())
+	for i := range ids {
+		ids[i] = t.Get(i).TXID
+	}
+	return ids
+}
+
+func hashes(hs ...util.Hash) []string {
+	ids := make([]string, len(hs))
+	for i, v := range hs {
+		ids[i] = hex.EncodeToString(v[:])
+	}
+	return ids
+}
+
+func TestTxListScansEveryPage(t *testing.T) {
+	save.UseStore(save.NewMemStore())
+	f := newFakeWallet(t)
+	var out []util.Hash
+	for height := uint64(1); height <= 5; height++ {
+		out = append([]util.Hash{f.add(false, height*10, height)}, out...)
+	}
+	in := f.add(true, 35, 0)
+
+	txlist := NewTxList("w", testHistoryCipher(t))
+	progress := 0
+	txlist.OnProgress = func() { progress++ }
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	want := hashes(out[0], out[1], in, out[2], out[3], out[4])
+	if got := txids(txlist); !slices.Equal(got, want) {
+		t.Errorf("transactions = %v, want %v", got, want)
+	}
+	if !slices.Equal(f.pages, []uint64{0, 1, 2}) {
+		t.Errorf("pages = %v, want every page once", f.pages)
+	}
+	if scanned, total, done := txlist.Progress(); scanned != 4 || total != 4 || !done {
+		t.Errorf("progress = %d/%d done=%v, want 4/4 done", scanned, total, done)
+	}
+	if progress != 4 {
+		t.Errorf("OnProgress called %d times, want once per page", progress)
+	}
+
+	tx := txlist.Get(0)
+	if tx.Height != 50 || tx.Amount != 5 || tx.Incoming || !tx.Time.Equal(time.Unix(50, 0)) {
+		t.Errorf("newest transaction = %+v", tx)
+	}
+}
+
+func TestTxListResumesAfterError(t *testing.T) {
+	save.UseStore(save.NewMemStore())
+	f := newFakeWallet(t)
+	for height := uint64(1); height <= 6; height++ {
+		f.add(false, height, height)
+	}
+	// the first transaction of the second page
+	f.failTx = f.txids[false][2]
+
+	aead := testHistoryCipher(t)
+	txlist := NewTxList("w", aead)
+	if err := txlist.Refresh(context.Background(), f); err == nil {
+		t.Fatal("the error was not returned")
+	}
+	if scanned, _, done := txlist.Progress(); scanned != 1 || done {
+		t.Errorf("progress = %d pages done=%v, want the first page only", scanned, done)
+	}
+
+	// as after a restart, the scan resumes from the cached history
+	f.reset()
+	txlist = NewTxList("w", aead)
+	if txlist.Len() != 2 {
+		t.Errorf("cached transactions = %d, want 2", txlist.Len())
+	}
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(f.pages, []uint64{1, 2}) {
+		t.Errorf("pages = %v, want the scan to resume from page 1", f.pages)
+	}
+	if len(f.fetched) != 4 {
+		t.Errorf("fetched %d transactions, want only the 4 missing ones", len(f.fetched))
+	}
+	if _, _, done := txlist.Progress(); !done || txlist.Len() != 6 {
+		t.Errorf("done = %v, transactions = %d, want all 6", done, txlist.Len())
+	}
+}
+
+func TestTxListIncrementalRefresh(t *testing.T) {
+	save.UseStore(save.NewMemStore())
+	f := newFakeWallet(t)
+	for height := uint64(1); height <= 6; height++ {
+		f.add(false, height, height)
+	}
+	txlist := NewTxList("w", nil)
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		newTxs int
+		pages  []uint64
+	}{
+		{"nothing new", 0, []uint64{0}},
+		{"new transaction", 1, []uint64{0}},
+		{"a page of new transactions", fakePageSize, []uint64{0, 1}},
+		{"more than a page", fakePageSize + 1, []uint64{0, 1}},
+	}
+	height := uint64(100)
+	for _, tt := range tests {
+		var added []util.Hash
+		for range tt.newTxs {
+			height++
+			added = append(added, f.add(false, height, height))
+		}
+		// the pages are fetched newest first
+		slices.Reverse(added)
+
+		f.reset()
+		if err := txlist.Refresh(context.Background(), f); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(f.pages, tt.pages) {
+			t.Errorf("%s: pages = %v, want %v", tt.name, f.pages, tt.pages)
+		}
+		if !slices.Equal(hashes(f.fetched...), hashes(added...)) {
+			t.Errorf("%s: fetched %v, want only the new transactions", tt.name, hashes(f.fetched...))
+		}
+		if got := txids(txlist); !slices.Equal(got, hashes(f.txids[false]...)) {
+			t.Errorf("%s: transactions = %v, want %v", tt.name, got, hashes(f.txids[false]...))
+		}
+	}
+}
+
+func TestTxListPendingTransaction(t *testing.T) {
+	save.UseStore(save.NewMemStore())
+	f := newFakeWallet(t)
+	f.add(false, 1, 1)
+	pending := f.add(false, 0, 2)
+
+	txlist := NewTxList("w", nil)
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if tx := txlist.Get(0); tx.Height != 0 || !tx.Time.IsZero() {
+		t.Errorf("pending transaction = %+v", tx)
+	}
+
+	// pending transactions are fetched again until they are in a block
+	f.reset()
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(f.fetched, []util.Hash{pending}) {
+		t.Errorf("fetched %v, want the pending transaction", hashes(f.fetched...))
+	}
+
+	f.confirm(pending, 7)
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if tx := txlist.Get(0); tx.Height != 7 || !tx.Time.Equal(time.Unix(7, 0)) {
+		t.Errorf("confirmed transaction = %+v", tx)
+	}
+
+	f.reset()
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.fetched) != 0 {
+		t.Errorf("fetched %v, confirmed transactions must not be fetched again", hashes(f.fetched...))
+	}
+}
+
+func TestTxListPendingTransactionOnLaterPage(t *testing.T) {
+	save.UseStore(save.NewMemStore())
+	f := newFakeWallet(t)
+	f.add(false, 1, 1)
+	f.add(false, 2, 2)
+	// a transaction can be mined before an older one which is still pending
+	pending := f.add(false, 0, 3)
+	f.add(false, 4, 4)
+
+	txlist := NewTxList("w", nil)
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	// the new transaction pushes the pending one to the second page
+	added := f.add(false, 5, 5)
+	f.confirm(pending, 3)
+	f.reset()
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(f.pages, []uint64{0, 1}) {
+		t.Errorf("pages = %v, want the scan to go on until the pending transaction", f.pages)
+	}
+	if !slices.Equal(hashes(f.fetched...), hashes(added, pending)) {
+		t.Errorf("fetched %v, want the new and the pending transaction", hashes(f.fetched...))
+	}
+	if got := txids(txlist); !slices.Equal(got, hashes(f.txids[false]...)) {
+		t.Errorf("transactions = %v, want %v", got, hashes(f.txids[false]...))
+	}
+	if scanned, total, done := txlist.Progress(); scanned != 4 || total != 4 || !done {
+		t.Errorf("progress = %d/%d done=%v, want the new page counted", scanned, total, done)
+	}
+
+	f.reset()
+	if err := txlist.Refresh(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(f.pages, []uint64{0}) || len(f.fetched) != 0 {
+		t.Errorf("pages = %v, fetched %v, want only the first page", f.pages, hashes(f.fetched...))
+	}
+}
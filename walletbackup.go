@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/virel-project/virel-blockchain/v3/wallet"
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+// openFailedDialog reports a wallet that could not be decrypted or parsed, and
+// offers to restore one of its backups
+func openFailedDialog(name, password string, openErr error) {
+	backups, err := save.ListBackups(name)
+	if err != nil || len(backups) == 0 {
+		fyne.Do(func() {
+			ErrorDialog(w, openErr)
+		})
+		return
+	}
+
+	fyne.Do(func() {
+		msg := widget.NewLabel(openErr.Error() + "\n\n" + T.BackupsAvailable)
+		msg.Wrapping = fyne.TextWrapWord
+
+		Dialog(w, T.OpenWallet, T.RestoreBackup, T.Cancel, msg, func(b bool) {
+			if b {
+				restoreBackupDialog(name, password, backups)
+			}
+		})
+	})
+}
+
+// restoreBackupDialog lets the user pick a backup. The backup is opened with
+// the password before it replaces the wallet, so that a working wallet is never
+// replaced by a broken one.
+func restoreBackupDialog(name, password string, backups []string) {
+	// backups made in the same second get a number, as the selection is
+	// matched by its label
+	labels := make([]string, len(backups))
+	seen := make(map[string]int, len(backups))
+	for i, v := range backups {
+		labels[i] = v
+		if t, err := save.BackupTime(v); err == nil {
+			labels[i] = T.FormatTimeSeconds(t)
+		}
+		seen[labels[i]]++
+		if n := seen[labels[i]]; n > 1 {
+			labels[i] = fmt.Sprintf("%s (%d)", labels[i], n)
+		}
+	}
+
+	backupSelect := widget.NewSelect(labels, func(s string) {})
+	backupSelect.SetSelectedIndex(0)
+
+	pass := widget.NewPasswordEntry()
+	pass.SetText(password)
+
+	formItems := []*widget.FormItem{
+		{Text: T.Backup, Widget: backupSelect},
+		{Text: T.Password, Widget: pass},
+	}
+
+	dialog.NewForm(T.RestoreBackup, T.RestoreBackup, T.Cancel, formItems, func(b bool) {
+		if !b {
+			return
+		}
+		if backupSelect.SelectedIndex() < 0 {
+			return
+		}
+		backup := backups[backupSelect.SelectedIndex()]
+		password := pass.Text
+
+		loadingPage(T.LoadingWallet)
+		go func() {
+			data, err := save.ReadBackup(name, backup)
+			if err != nil {
+				ErrorDialog(w, err)
+				pageOpen()
+				return
+			}
+
+			wall, err := wallet.OpenWallet(nodeManager.Best(), data, password)
+			if err != nil {
+				ErrorDialog(w, fmt.Errorf(T.FailedToOpenBackup, err))
+				pageOpen()
+				return
+			}
+
+			err = save.RestoreBackup(name, backup)
+			if err != nil {
+				ErrorDialog(w, err)
+				pageOpen()
+				return
+			}
+
+			pageWallet(wall, name)
+		}()
+	}, w).Show()
+}
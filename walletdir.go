@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+const walletDirEnv = "VIREL_WALLET_DIR"
+
+var walletDirFlag = flag.String("wallet-dir", "", "directory where the wallets are stored (or set "+walletDirEnv+")")
+
+// setupWalletDir selects the wallet directory. The --wallet-dir flag takes
+// precedence over the environment variable, then over the directory chosen in
+// the settings. When none is set, the per-OS default is used and the wallets
+// of the working directory are migrated to it.
+func setupWalletDir() {
+	dir := *walletDirFlag
+	if dir == "" {
+		dir = os.Getenv(walletDirEnv)
+	}
+	if dir == "" {
+		dir, _ = save.ReadWalletDirSetting()
+	}
+
+	migrate := false
+	if dir == "" {
+		var err error
+		dir, err = save.DefaultWalletDir()
+		if err != nil {
+			fmt.Println("could not find the default wallet directory:", err)
+			return
+		}
+		migrate = true
+	}
+
+	err := save.SetWalletDir(dir)
+	if err != nil {
+		fmt.Println("could not use wallet directory", dir, err)
+		return
+	}
+	fmt.Println("wallet directory:", save.WalletDir())
+
+	if migrate {
+		err = save.MigrateWallets(".")
+		if err != nil {
+			fmt.Println("wallet migration:", err)
+		}
+	}
+}
+
+// walletDirDialog lets the user pick a new wallet directory
+func walletDirDialog(onChange func()) {
+	dirLbl := widget.NewLabel(save.WalletDir())
+	dirLbl.Wrapping = fyne.TextWrapBreak
+
+	var d dialog.Dialog
+
+	browseBtn := widget.NewButton(T.ChangeWalletDir, func() {
+		fd := dialog.NewFolderOpen(func(lu fyne.ListableURI, err error) {
+			if err != nil {
+				ErrorDialog(w, err)
+				return
+			}
+			if lu == nil {
+				return
+			}
+			err = save.SetWalletDir(lu.Path())
+			if err == nil {
+				err = save.SaveWalletDirSetting(save.WalletDir())
+			}
+			if err != nil {
+				ErrorDialog(w, err)
+				return
+			}
+			d.Hide()
+			onChange()
+		}, w)
+		if lu, err := storage.ListerForURI(storage.NewFileURI(save.WalletDir())); err == nil {
+			fd.SetLocation(lu)
+		}
+		fd.Show()
+	})
+
+	resetBtn := widget.NewButton(T.ResetWalletDir, func() {
+		dir, err := save.DefaultWalletDir()
+		if err == nil {
+			err = save.SetWalletDir(dir)
+		}
+		if err == nil {
+			err = save.SaveWalletDirSetting("")
+		}
+		if err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		d.Hide()
+		onChange()
+	})
+
+	d = dialog.NewCustom(T.WalletDir, T.Cancel, container.NewVBox(dirLbl, browseBtn, resetBtn), w)
+	d.Show()
+}
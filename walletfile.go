@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/virel-project/virel-blockchain/v3/wallet"
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+// exportWallet saves a copy of the wallet file outside of the wallet storage
+// Note: name is already a PathEscaped string
+func exportWallet(name string) {
+	data, err := save.ReadWallet(name)
+	if err != nil {
+		ErrorDialog(w, fmt.Errorf(T.FailedToExportWallet, err))
+		return
+	}
+	exportFile(name+".keys", data)
+}
+
+// importWalletDialog asks for the name and password of an imported wallet
+// file. The file is only saved once it has been opened successfully with the
+// password.
+func importWalletDialog(filename string, data []byte) {
+	defaultName := strings.TrimSuffix(filename, ".keys")
+	if n, err := url.PathUnescape(defaultName); err == nil {
+		defaultName = n
+	}
+
+	walletName := widget.NewEntry()
+	walletName.SetText(defaultName)
+	walletName.Validator = validateWalletName
+	walletPass := widget.NewPasswordEntry()
+
+	formItems := []*widget.FormItem{
+		{Text: T.WalletName, Widget: walletName},
+		{Text: T.Password, Widget: walletPass},
+	}
+
+	dialog.NewForm(T.ImportWallet, T.ImportWallet, T.Cancel, formItems, func(b bool) {
+		if !b {
+			return
+		}
+		wallName := save.WalletFileName(walletName.Text)
+		password := walletPass.Text
+
+		loadingPage(T.LoadingWallet)
+		go func() {
+			_, err := save.ReadWallet(wallName)
+			if err == nil {
+				ErrorDialog(w, fmt.Errorf(T.WalletAlreadyExists, walletName.Text))
+				pageOpen()
+				return
+			}
+
+			wall, err := wallet.OpenWallet(nodeManager.Best(), data, password)
+			if err != nil {
+				ErrorDialog(w, fmt.Errorf(T.FailedToImportWallet, err))
+				pageOpen()
+				return
+			}
+
+			err = save.SaveWallet(wallName, data)
+			if err != nil {
+				ErrorDialog(w, fmt.Errorf(T.FailedToImportWallet, err))
+				pageOpen()
+				return
+			}
+
+			err = save.SaveWalletMeta(wallName, newWalletMeta(walletName.Text))
+			if err != nil {
+				fmt.Println("failed to save wallet metadata:", err)
+			}
+
+			pageWallet(wall, wallName)
+		}()
+	}, w).Show()
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/virel-project/virel-blockchain/v3/wallet"
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+// Note: name is already a PathEscaped string
+func renameWalletDialog(name string) {
+	meta, err := save.ReadWalletMeta(name)
+	if err != nil {
+		ErrorDialog(w, err)
+		return
+	}
+	displayName := meta.Name
+
+	newName := widget.NewEntry()
+	newName.SetText(displayName)
+	newName.Validator = validateWalletName
+
+	formItems := []*widget.FormItem{
+		{Text: T.WalletName, Widget: newName},
+	}
+
+	dialog.NewForm(T.RenameWallet, T.Confirm, T.Cancel, formItems, func(b bool) {
+		if !b {
+			return
+		}
+		err := save.RenameWallet(name, newName.Text)
+		if err != nil {
+			ErrorDialog(w, fmt.Errorf(T.FailedToRenameWallet, err))
+			return
+		}
+		pageOpen()
+	}, w).Show()
+}
+
+// deleteWalletDialog moves the wallet to the trash, after checking its password
+// and asking the user to type its name
+// Note: name is already a PathEscaped string
+func deleteWalletDialog(name string) {
+	meta, err := save.ReadWalletMeta(name)
+	if err != nil {
+		ErrorDialog(w, err)
+		return
+	}
+	displayName := meta.Name
+
+	walletPass := widget.NewPasswordEntry()
+	confirmName := widget.NewEntry()
+	confirmName.SetPlaceHolder(displayName)
+	confirmName.Validator = func(s string) error {
+		if s != displayName {
+			return errors.New(T.ErrConfirmWalletName)
+		}
+		return nil
+	}
+
+	msg := widget.NewLabel(fmt.Sprintf(T.DeleteWalletConfirm, displayName))
+	msg.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem(T.Password, walletPass),
+		&widget.FormItem{Text: T.WalletName, Widget: confirmName, HintText: T.TypeWalletName},
+	)
+
+	Dialog(w, T.DeleteWallet, T.DeleteWallet, T.Cancel, container.NewVBox(msg, form), func(b bool) {
+		if !b {
+			return
+		}
+		if err := confirmName.Validate(); err != nil {
+			ErrorDialog(w, err)
+			return
+		}
+		password := walletPass.Text
+
+		go func() {
+			data, err := save.ReadWallet(name)
+			if err != nil {
+				fyne.Do(func() { ErrorDialog(w, err) })
+				return
+			}
+			// opening the wallet is the only way to check the password
+			_, err = wallet.OpenWallet(nodeManager.Best(), data, password)
+			if err == nil {
+				err = save.DeleteWallet(name)
+			}
+			fyne.Do(func() {
+				if err != nil {
+					ErrorDialog(w, fmt.Errorf(T.FailedToDeleteWallet, err))
+					return
+				}
+				pageOpen()
+				InfoDialog(w, T.DeleteWallet, T.WalletMovedToTrash)
+			})
+		}()
+	})
+}
+
+// trashDialog lists the deleted wallets and lets the user restore them
+func trashDialog() {
+	trashed, err := save.ListTrash()
+	if err != nil {
+		ErrorDialog(w, err)
+		return
+	}
+
+	var d dialog.Dialog
+
+	list := container.NewVBox()
+	if len(trashed) == 0 {
+		list.Add(widget.NewLabel(T.TrashEmpty))
+	}
+	for _, v := range trashed {
+		lbl := widget.NewLabel(v.DisplayName + "  (" + T.FormatTime(v.Deleted) + ")")
+		restoreBtn := widget.NewButtonWithIcon(T.Restore, theme.ContentUndoIcon(), func() {
+			err := save.RestoreFromTrash(v)
+			if err != nil {
+				ErrorDialog(w, fmt.Errorf(T.FailedToRestoreWallet, err))
+				return
+			}
+			d.Hide()
+			pageOpen()
+		})
+		list.Add(container.NewBorder(nil, nil, nil, restoreBtn, lbl))
+	}
+
+	d = dialog.NewCustom(T.Trash, T.Cancel, container.NewVScroll(list), w)
+	d.Resize(fyne.NewSize(500, 400))
+	d.Show()
+}
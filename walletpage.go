@@ -0,0 +1,543 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/virel-project/virel-blockchain/v3/address"
+	"github.com/virel-project/virel-blockchain/v3/config"
+	"github.com/virel-project/virel-blockchain/v3/transaction"
+	"github.com/virel-project/virel-blockchain/v3/wallet"
+	"github.com/virel-project/virel-gui/v2/lang"
+	"github.com/virel-project/virel-gui/v2/mylayout"
+	"github.com/virel-project/virel-gui/v2/mywidget"
+	"github.com/virel-project/virel-gui/v2/save"
+)
+
+// WalletPage is the page of an open wallet. Its views are built from the
+// current translation, and are rebuilt when the language changes without
+// closing the wallet.
+type WalletPage struct {
+	Wallet *wallet.Wallet
+	// Name is the PathEscaped name of the wallet file
+	Name string
+
+	sess      *Session
+	txlist    *TxList
+	scheduler *RefreshScheduler
+
+	// result of the last refresh, used to render the status bar
+	refreshed  bool
+	refreshErr error
+	offline    bool
+	delay      time.Duration
+
+	// the views are only accessed from the UI goroutine
+	content       fyne.CanvasObject
+	tabs          *container.AppTabs
+	yourBalance   *mywidget.Card
+	stakedBalance *mywidget.Card
+	recipient     *widget.Entry
+	amount        *widget.Entry
+	history       *widget.Table
+	historyBar    *widget.ProgressBar
+	historyStatus *fyne.Container
+	staking       *StakingTab
+	statusLabel   *widget.Label
+	retryBtn      *widget.Button
+}
+
+// Note: name is already a PathEscaped string
+func pageWallet(wall *wallet.Wallet, name string) {
+	aead, err := historyCipher(wall)
+	if err != nil {
+		fmt.Println("transaction history will not be cached:", err)
+	}
+	p := &WalletPage{
+		Wallet:    wall,
+		Name:      name,
+		sess:      OpenSession(wall, name),
+		txlist:    NewTxList(name, aead),
+		scheduler: NewRefreshScheduler(),
+	}
+
+	err = save.UpdateWalletMeta(name, func(meta *save.WalletMeta) {
+		meta.LastOpened = time.Now()
+		meta.LastBalance = wall.GetBalance()
+		if meta.Network == "" {
+			meta.Network = NETWORK
+		}
+	})
+	if err != nil {
+		fmt.Println("failed to save wallet metadata:", err)
+	}
+
+	fyne.DoAndWait(func() {
+		p.build()
+		w.SetContent(p.content)
+	})
+
+	// the views must be built before the refresh loop updates them
+	p.txlist.OnProgress = func() {
+		fyne.Do(func() {
+			p.history.Refresh()
+			p.renderHistoryProgress()
+		})
+	}
+	p.sess.Go(p.refreshLoop)
+}
+
+// rebuild recreates the views with the current translation, keeping the
+// selected tab and the transfer form input
+func (p *WalletPage) rebuild() {
+	tab := p.tabs.SelectedIndex()
+	recipient := p.recipient.Text
+	amount := p.amount.Text
+
+	p.build()
+
+	p.tabs.SelectIndex(tab)
+	p.recipient.SetText(recipient)
+	p.amount.SetText(amount)
+
+	w.SetContent(p.content)
+}
+
+func (p *WalletPage) build() {
+	wall := p.Wallet
+
+	p.yourBalance = mywidget.NewCard(a, theme.Color(theme.ColorNamePrimary),
+		formatCoin(wall.GetBalance()), T.Balance, T.BalanceCopied)
+	p.stakedBalance = mywidget.NewCard(a, theme.Color(theme.ColorNameButton),
+		formatCoin(wall.GetStakedBalance()), T.StakedBalance, T.StakedBalanceCopied)
+	yourAddress := mywidget.NewCard(a, theme.Color(theme.ColorNameButton), wall.GetAddress().String(),
+		T.Address, T.AddressCopied)
+
+	cardsGrid := container.New(mylayout.NewWrapLayout(800),
+		p.yourBalance, p.stakedBalance, yourAddress)
+
+	myWallet := container.NewPadded(container.NewVBox(
+		cardsGrid,
+		layout.NewSpacer(),
+		// widget.NewLabel(T.RecentTransactions),
+		//list,
+	))
+
+	mySend := p.buildTransfer()
+
+	p.staking = CreateStakingTab(wall)
+
+	historyTab := p.buildHistory()
+
+	settingsCont := p.buildSettings()
+
+	p.tabs = container.NewAppTabs(
+		container.NewTabItemWithIcon(T.TabHome, theme.HomeIcon(), myWallet),
+		container.NewTabItemWithIcon(T.TabTransfer, theme.MailSendIcon(), mySend),
+		container.NewTabItemWithIcon(T.TabHistory, theme.HistoryIcon(), historyTab),
+		container.NewTabItemWithIcon(T.TabStaking, theme.StorageIcon(), p.staking.Container()),
+		container.NewTabItemWithIcon(T.Settings, theme.SettingsIcon(), settingsCont),
+	)
+
+	p.statusLabel = widget.NewLabel(T.StatusConnected)
+	p.retryBtn = widget.NewButtonWithIcon(T.RetryNow, theme.ViewRefreshIcon(), p.scheduler.RetryNow)
+	p.retryBtn.Hide()
+	statusBar := mywidget.NewBar(theme.Color(theme.ColorNameHeaderBackground), p.statusLabel, layout.NewSpacer(), p.retryBtn)
+
+	p.renderStatus()
+
+	p.content = container.NewBorder(nil, statusBar, nil, nil, p.tabs)
+}
+
+func (p *WalletPage) buildTransfer() fyne.CanvasObject {
+	wall := p.Wallet
+
+	recipient := widget.NewEntry()
+	recipient.Validator = func(s string) error {
+		if len(s) < 5 {
+			return errors.New(T.InvalidWallet)
+		}
+		_, err := address.FromString(s)
+		if err != nil {
+			return errors.New(T.InvalidWallet)
+		}
+		return nil
+	}
+
+	amount := widget.NewEntry()
+	amount.Validator = func(s string) error {
+		amt, err := parseAmount(s)
+		if err != nil {
+			return amountError(err)
+		}
+		if amt == 0 {
+			return errors.New(T.ErrAmountZero)
+		}
+		return nil
+	}
+
+	recipient.Enable()
+	amount.Enable()
+
+	p.recipient = recipient
+	p.amount = amount
+
+	sendForm := &widget.Form{
+		Items: []*widget.FormItem{ // we can specify items in the constructor
+			{Text: T.Recipient, Widget: recipient},
+			{Text: T.TransferAmount, Widget: amount},
+		},
+
+		SubmitText: T.Transfer,
+	}
+	sendForm.OnSubmit = func() { // optional, handle form submission
+
+		log.Info("Form submitted:", recipient.Text)
+		log.Info("amount:", amount.Text)
+
+		walletPass := widget.NewEntry()
+		walletPass.Password = true
+		walletPass.Validator = func(s string) error {
+			if len(s) == 0 {
+				return errors.New(T.FieldRequired)
+			}
+			return nil
+		}
+
+		dlg := dialog.NewCustomConfirm(T.TransferConfirm, T.Confirm, T.Cancel, container.NewVBox(
+			widget.NewLabel(T.ReviewTransferDetails),
+			widget.NewLabel(T.TransferFields.Format(lang.Args{
+				"amount":    amount.Text,
+				"ticker":    TICKER,
+				"recipient": recipient.Text,
+			})),
+			widget.NewForm(widget.NewFormItem(T.Password, walletPass)),
+		), func(ok bool) {
+			if !ok {
+				return
+			}
+
+			if walletPass.Text != string(wall.GetPassword()) {
+				ErrorDialog(w, errors.New(T.PasswordNotMatch))
+				return
+			}
+
+			amt, err := parseAmount(amount.Text)
+			if err != nil {
+				ErrorDialog(w, amountError(err))
+				return
+			}
+			if amt == 0 {
+				ErrorDialog(w, errors.New(T.ErrAmountZero))
+				return
+			}
+
+			recv, err := address.FromString(recipient.Text)
+			if err != nil {
+				ErrorDialog(w, errors.New(T.InvalidWallet))
+				return
+			}
+
+			txn, err := wall.Transfer([]transaction.Output{
+				{
+					Amount:    amt,
+					Recipient: recv.Addr,
+					PaymentId: recv.PaymentId,
+				},
+			}, true)
+			if err != nil {
+				ErrorDialog(w, fmt.Errorf(T.FailedToCreateTx, err))
+				return
+			}
+
+			dialog.NewCustomConfirm(T.ConfirmTransfer, T.Confirm, T.Cancel, container.NewVBox(
+				widget.NewLabel(T.Recipient+": "+recv.String()),
+				widget.NewLabel(T.TransferAmount+": "+formatCoin(amt)+" "+TICKER),
+				widget.NewLabel(T.TxFee+": "+formatCoin(txn.Fee)+" "+TICKER),
+				widget.NewLabel(T.TXID+": "+txn.Hash().String()),
+			), func(ok bool) {
+				if !ok {
+					return
+				}
+
+				res, err := wall.SubmitTx(txn)
+				if err != nil {
+					ErrorDialog(w, fmt.Errorf(T.FailedToSubmitTx, err))
+					return
+				}
+
+				InfoDialog(w, T.TransferSuccess, T.TXID+": "+res.TXID.String())
+
+			}, w).Show()
+
+		}, w)
+
+		dlg.Show()
+
+	}
+
+	sendTitle := NewTitle(T.Transfer)
+	return container.NewVBox(sendTitle, sendForm)
+}
+
+func (p *WalletPage) buildHistory() fyne.CanvasObject {
+	wall := p.Wallet
+	txlist := p.txlist
+
+	historyList := widget.NewTableWithHeaders(
+		func() (int, int) {
+			return txlist.Len(), 4
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel(hex.EncodeToString(make([]byte, 32)))
+		},
+		func(i widget.TableCellID, co fyne.CanvasObject) {
+			//lbl := co.(*fyne.Container).Objects[0].(*widget.Label)
+			lbl := co.(*widget.Label)
+
+			x := txlist.Get(i.Row)
+
+			switch i.Col {
+			case 0: // time
+				if x.Height == 0 {
+					lbl.SetText(T.Pending)
+				} else {
+					lbl.SetText(T.FormatTime(x.Time))
+				}
+			case 1: // txid
+				lbl.SetText(x.TXID)
+			case 2: // amount
+				if x.Incoming {
+					lbl.SetText("+" + formatCoin(x.Amount))
+				} else {
+					lbl.SetText("-" + formatCoin(x.Amount))
+				}
+			case 3: // confs
+				var confs uint64
+				if height := wall.GetHeight(); x.Height != 0 && height > x.Height {
+					confs = height - x.Height
+				}
+				lbl.SetText(strconv.FormatUint(confs, 10))
+			}
+		},
+	)
+	historyList.ShowHeaderColumn = false
+	historyList.ShowHeaderRow = true
+
+	timeWidth := max(widget.NewLabel(T.FormatTime(time.Now())).MinSize().Width,
+		widget.NewLabel(T.Pending).MinSize().Width)
+	amtWidth := widget.NewLabel("+" + formatCoin(1000*config.COIN)).MinSize().Width
+
+	historyList.SetColumnWidth(0, timeWidth)
+	historyList.SetColumnWidth(2, amtWidth)
+	historyList.SetColumnWidth(3, timeWidth)
+
+	historyList.CreateHeader = func() fyne.CanvasObject {
+		lbl := widget.NewLabel("")
+		lbl.Truncation = fyne.TextTruncateEllipsis
+		return lbl
+	}
+	historyList.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+		lbl := template.(*widget.Label)
+
+		switch id.Col {
+		case 0:
+			lbl.SetText(T.Time)
+		case 1:
+			lbl.SetText(T.TXID)
+		case 2:
+			lbl.SetText(T.TransferAmount)
+		case 3:
+			lbl.SetText(T.Confirmations)
+		}
+	}
+
+	p.history = historyList
+	p.historyBar = widget.NewProgressBar()
+	p.historyStatus = container.NewVBox(widget.NewLabel(T.LoadingHistory), p.historyBar)
+	p.renderHistoryProgress()
+
+	return container.NewBorder(p.historyStatus, nil, nil, nil, historyList)
+}
+
+// renderHistoryProgress shows the progress of the history scan until every
+// page has been scanned
+func (p *WalletPage) renderHistoryProgress() {
+	scanned, total, done := p.txlist.Progress()
+	if done {
+		p.historyStatus.Hide()
+		return
+	}
+	p.historyBar.Max = float64(total)
+	p.historyBar.SetValue(float64(scanned))
+	p.historyStatus.Show()
+}
+
+func (p *WalletPage) buildSettings() fyne.CanvasObject {
+	wall := p.Wallet
+
+	seedBtn := widget.NewButton(T.DisplaySeed, func() {
+		passEntry := widget.NewEntry()
+		passEntry.Password = true
+
+		content := container.NewVBox(passEntry)
+
+		d := dialog.NewCustomConfirm(T.InputPassword, T.ViewSeed, T.Cancel, content, func(confirm bool) {
+			if !confirm {
+				return
+			}
+
+			if passEntry.Text != string(wall.GetPassword()) {
+				ErrorDialog(w, errors.New(T.PasswordNotMatch))
+				return
+			}
+
+			displaySeedDialog(wall)
+		}, w)
+		d.Show()
+	})
+
+	nodeLbl := widget.NewLabel(T.NodeAddress + ": " + wall.GetRpcDaemonAddress())
+
+	changeNodeBtn := widget.NewButton(T.ChangeNode, func() {
+		pageNodes(func() {
+			if !slices.Contains(nodeManager.Urls(), wall.GetRpcDaemonAddress()) {
+				wall.SetRpcDaemonAddress(nodeManager.Best())
+			}
+			nodeLbl.SetText(T.NodeAddress + ": " + wall.GetRpcDaemonAddress())
+			w.SetContent(p.content)
+		})
+	})
+
+	closeWallet := func(next func()) {
+		loadingPage(T.ClosingWallet)
+		go func() {
+			CloseSession()
+			fyne.Do(next)
+		}()
+	}
+	switchWalletBtn := widget.NewButtonWithIcon(T.SwitchWallet, theme.FolderOpenIcon(), func() {
+		closeWallet(pageOpen)
+	})
+	closeWalletBtn := widget.NewButtonWithIcon(T.CloseWallet, theme.LogoutIcon(), func() {
+		closeWallet(pageHome)
+	})
+
+	settingsCont := container.NewVBox(NewTitle(T.Settings), seedBtn, nodeLbl, changeNodeBtn)
+
+	prefs := widget.NewForm(widget.NewFormItem(T.Language, languageSelect(p.rebuild)))
+	if settings, err := save.LoadSettings(); err == nil {
+		prefs.Append(T.Theme, themeSelect(settings.Theme))
+	}
+	settingsCont.Add(prefs)
+
+	// the browser handles networking on the web build. The wallet folder can
+	// only be changed from the wallet list, while no wallet is open.
+	if runtime.GOOS != "js" {
+		settingsCont.Add(widget.NewButton(T.ProxySettings, proxyDialog))
+	}
+	settingsCont.Add(layout.NewSpacer())
+	settingsCont.Add(switchWalletBtn)
+	settingsCont.Add(closeWalletBtn)
+
+	return settingsCont
+}
+
+// renderStatus shows the result of the last refresh in the status bar
+func (p *WalletPage) renderStatus() {
+	if !p.refreshed {
+		return
+	}
+	wall := p.Wallet
+	err := p.refreshErr
+
+	if err != nil && nodeManager.Untrusted(wall.GetRpcDaemonAddress()) {
+		p.statusLabel.SetText(T.StatusUntrusted + " " + wall.GetRpcDaemonAddress())
+	} else if p.offline {
+		p.statusLabel.SetText(fmt.Sprintf(T.StatusOffline, p.delay.Round(time.Second)))
+	} else if err != nil {
+		p.statusLabel.SetText(T.StatusError)
+	} else if nodeManager.Disagrees(wall.GetRpcDaemonAddress()) {
+		p.statusLabel.SetText(T.StatusNodeDisagrees + " " + wall.GetRpcDaemonAddress())
+	} else {
+		p.statusLabel.SetText(T.StatusConnected + " " + wall.GetRpcDaemonAddress())
+	}
+
+	if err != nil {
+		p.retryBtn.Show()
+	} else {
+		p.retryBtn.Hide()
+	}
+}
+
+func (p *WalletPage) refreshLoop(ctx context.Context) {
+	wall := p.Wallet
+	scheduler := p.scheduler
+
+	lastBalance := wall.GetBalance()
+
+	for ctx.Err() == nil {
+		refreshErr := nodeManager.Refresh(wall)
+		if ctx.Err() != nil {
+			return
+		}
+		if refreshErr != nil && scheduler.Failures() == 0 {
+			fmt.Println("failed to refresh:", refreshErr)
+		}
+		scheduler.Done(refreshErr)
+		offline := scheduler.Offline()
+		delay := scheduler.Delay()
+
+		fyne.Do(func() {
+			p.refreshed = true
+			p.refreshErr = refreshErr
+			p.offline = offline
+			p.delay = delay
+			p.renderStatus()
+
+			// when offline, the cards keep showing the last known balances
+			p.yourBalance.SetTitle(formatCoin(wall.GetBalance()))
+			p.stakedBalance.SetTitle(formatCoin(wall.GetStakedBalance()))
+		})
+		if refreshErr == nil {
+			err := p.txlist.Refresh(ctx, wall)
+			if err != nil && ctx.Err() == nil {
+				fmt.Println("error fetching tx list:", err)
+			}
+			fyne.Do(func() {
+				if ctx.Err() != nil {
+					return
+				}
+				p.staking.Refresh()
+				p.history.Refresh()
+				p.renderHistoryProgress()
+			})
+
+			if balance := wall.GetBalance(); balance != lastBalance {
+				lastBalance = balance
+				err = save.UpdateWalletMeta(p.Name, func(meta *save.WalletMeta) {
+					meta.LastBalance = balance
+				})
+				if err != nil {
+					fmt.Println("failed to save wallet metadata:", err)
+				}
+			}
+		}
+
+		if !scheduler.Wait(ctx, delay) {
+			return
+		}
+	}
+}